@@ -0,0 +1,201 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/config/genesis"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain/archive"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/urfave/cli"
+)
+
+// exportAction writes a range of blocks from the chain database into an
+// archive file. The database is opened in read-only mode.
+func exportAction(ctx *cli.Context) error {
+	if err := loadCommandConfig(ctx); err != nil {
+		return err
+	}
+
+	from := ctx.Uint64(ExportFromFlag.Name)
+	to := ctx.Uint64(ExportToFlag.Name)
+
+	drvr, err := database.From(cfg.Get().Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	db, err := drvr.Open(cfg.Get().Database.Dir, true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	// By default, export up to the chain tip
+	if !ctx.IsSet(ExportToFlag.Name) {
+		if err = db.View(func(t database.Transaction) error {
+			var e error
+			to, e = t.FetchCurrentHeight()
+			return e
+		}); err != nil {
+			return err
+		}
+	}
+
+	if from > to {
+		return fmt.Errorf("invalid range %d-%d", from, to)
+	}
+
+	f, err := os.Create(ctx.String(ExportOutFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	bw := bufio.NewWriter(f)
+	w := archive.NewWriter(bw)
+
+	for height := from; height <= to; height++ {
+		var blk *block.Block
+
+		if err = db.View(func(t database.Transaction) error {
+			hash, e := t.FetchBlockHashByHeight(height)
+			if e != nil {
+				return e
+			}
+
+			blk, e = t.FetchBlock(hash)
+			return e
+		}); err != nil {
+			return fmt.Errorf("block %d: %w", height, err)
+		}
+
+		if err = w.WriteBlock(blk); err != nil {
+			return err
+		}
+	}
+
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+
+	log.WithField("from", from).WithField("to", to).
+		WithField("file", f.Name()).Info("export completed")
+
+	return f.Sync()
+}
+
+// importAction replays all blocks of an archive file through the Chain
+// acceptance procedure. Blocks already known are skipped, as long as they match
+// the stored ones. It requires a running Rusk service but the node itself must
+// be stopped.
+func importAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("archive file is required")
+	}
+
+	if err := loadCommandConfig(ctx); err != nil {
+		return err
+	}
+
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	drvr, err := database.From(cfg.Get().Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	db, err := drvr.Open(cfg.Get().Database.Dir, false)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	gctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Get().RPC.Rusk.ConnectionTimeout)*time.Millisecond)
+	defer cancel()
+
+	proxy, ruskConn := setupGRPCClients(gctx)
+
+	defer func() {
+		_ = ruskConn.Close()
+	}()
+
+	l := chain.NewDBLoader(db, genesis.Decode())
+
+	// No consensus loop is needed to accept blocks.
+	c, err := chain.New(context.Background(), db, eventbus.New(), rpcbus.New(), l, l, nil, proxy, nil)
+	if err != nil {
+		return err
+	}
+
+	r := archive.NewReader(bufio.NewReader(f))
+
+	var imported int
+
+	for {
+		blk, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = c.ImportBlock(*blk)
+		if err == chain.ErrBlockAlreadyAccepted {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("block %d: %w", blk.Header.Height, err)
+		}
+
+		imported++
+	}
+
+	log.WithField("imported", imported).Info("import completed")
+	return nil
+}
+
+// loadCommandConfig loads the node configurations for a subcommand. The config
+// file is taken from the global config flag only.
+func loadCommandConfig(ctx *cli.Context) error {
+	configFile := ctx.GlobalString(ConfigFlag.Name)
+
+	return cfg.Load("dusk", nil, func() (string, error) {
+		return configFile, nil
+	})
+}
@@ -0,0 +1,109 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/client"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// backupAction requests an online backup of the chain database from a
+// running node and writes it into a tar archive. The archive is written into
+// a temporary file first, so that an interrupted backup never leaves a
+// truncated archive behind.
+func backupAction(ctx *cli.Context) error {
+	if err := loadCommandConfig(ctx); err != nil {
+		return err
+	}
+
+	conn, closeConn, err := dialNode()
+	if err != nil {
+		return err
+	}
+
+	defer closeConn()
+
+	stream, err := backup.NewBackupClient(conn).Backup(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	r := backup.NewReader(stream)
+
+	out := ctx.String(BackupOutFlag.Name)
+	tmp := out + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+	}()
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, out); err != nil {
+		return err
+	}
+
+	log.WithField("file", out).WithField("size", n).Info("backup completed")
+	return nil
+}
+
+// dialNode connects to the gRPC server of a running node. If the node requires
+// a session, it is created on connecting and dropped on closing.
+func dialNode() (*grpc.ClientConn, func(), error) {
+	conf := cfg.Get().RPC
+	opts := []grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock(), grpc.WithAuthority("dummy")}
+
+	if conf.RequireSession {
+		nc := client.New(conf.Network, conf.Address)
+
+		conn, err := nc.GetSessionConn(opts...)
+		if err != nil {
+			nc.Close()
+			return nil, nil, err
+		}
+
+		return conn, func() {
+			nc.GracefulClose(opts...)
+			_ = conn.Close()
+		}, nil
+	}
+
+	addr := conf.Address
+	if conf.Network == "unix" {
+		addr = "unix://" + conf.Address
+	}
+
+	conn, err := grpc.DialContext(context.Background(), addr, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, func() {
+		_ = conn.Close()
+	}, nil
+}
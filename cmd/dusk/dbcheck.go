@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/urfave/cli"
+)
+
+// dbcheckAction verifies the consistency of the chain database and prints a
+// JSON report to stdout. The database is opened in read-only mode.
+func dbcheckAction(ctx *cli.Context) error {
+	if err := loadCommandConfig(ctx); err != nil {
+		return err
+	}
+
+	drvr, err := database.From(cfg.Get().Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	db, err := drvr.Open(cfg.Get().Database.Dir, true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	var p *user.Provisioners
+
+	if ctx.Bool(DBCheckCertificatesFlag.Name) {
+		if p, err = fetchProvisioners(); err != nil {
+			return err
+		}
+	}
+
+	report, err := chain.CheckDatabase(db, p)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintln(os.Stdout, string(out)); err != nil {
+		return err
+	}
+
+	if len(report.Issues) > 0 {
+		return fmt.Errorf("%d inconsistencies found", len(report.Issues))
+	}
+
+	return nil
+}
+
+// fetchProvisioners retrieves the current provisioners set from Rusk. It is
+// the set in force at the chain tip, as long as Rusk is in sync with it.
+func fetchProvisioners() (*user.Provisioners, error) {
+	timeout := time.Duration(cfg.Get().RPC.Rusk.ConnectionTimeout) * time.Millisecond
+
+	gctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	proxy, ruskConn := setupGRPCClients(gctx)
+
+	defer func() {
+		_ = ruskConn.Close()
+	}()
+
+	p, err := proxy.Executor().GetProvisioners(gctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
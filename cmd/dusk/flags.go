@@ -26,6 +26,57 @@ var (
 		Name:  "datadir",
 		Usage: "Data directory for the node",
 	}
+
+	// ExportFromFlag flag to set the height of the first exported block.
+	ExportFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Height of the first block to export",
+	}
+	// ExportToFlag flag to set the height of the last exported block.
+	ExportToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Height of the last block to export (default: chain tip)",
+	}
+	// ExportOutFlag flag to set the archive file to export into.
+	ExportOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Archive file to export blocks into",
+		Value: "chain.dat",
+	}
+
+	// DBCheckCertificatesFlag flag to enable block certificates verification.
+	// Only the certificates of the blocks of the tip epoch are verified, as
+	// Rusk provides the current provisioners set only. The heights of the
+	// unchecked blocks are listed in the report.
+	DBCheckCertificatesFlag = cli.BoolFlag{
+		Name:  "certificates",
+		Usage: "Verify the certificates of the blocks of the tip epoch (requires a running Rusk service)",
+	}
+
+	// BackupOutFlag flag to set the archive file to write a backup into.
+	BackupOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Archive file to write the backup into",
+		Value: "backup.tar",
+	}
+
+	// ReplayFromFlag flag to set the height of the first replayed block.
+	ReplayFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Height of the first block to replay",
+		Value: 1,
+	}
+	// ReplayToFlag flag to set the height of the last replayed block.
+	ReplayToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Height of the last block to replay (default: chain tip)",
+	}
+	// ReplayRuskFlag flag to set the address of the Rusk service to replay
+	// blocks against.
+	ReplayRuskFlag = cli.StringFlag{
+		Name:  "rusk",
+		Usage: "Address of the Rusk service to replay blocks against (default: rpc.rusk.address)",
+	}
 )
 
 var (
@@ -43,6 +43,41 @@ func init() {
 			Usage:   "serializes the genesis block and prints it",
 			Action:  genesis.Action,
 		},
+		{
+			Name:   "export",
+			Usage:  "exports a range of blocks into an archive file",
+			Flags:  []cli.Flag{ExportFromFlag, ExportToFlag, ExportOutFlag},
+			Action: exportAction,
+		},
+		{
+			Name:      "import",
+			Usage:     "imports blocks from an archive file",
+			ArgsUsage: "<archive file>",
+			Action:    importAction,
+		},
+		{
+			Name:   "dbcheck",
+			Usage:  "verifies the consistency of the chain database",
+			Flags:  []cli.Flag{DBCheckCertificatesFlag},
+			Action: dbcheckAction,
+		},
+		{
+			Name:   "migrate",
+			Usage:  "upgrades the chain database to the current schema version",
+			Action: migrateAction,
+		},
+		{
+			Name:   "backup",
+			Usage:  "writes an online backup of the chain database of a running node",
+			Flags:  []cli.Flag{BackupOutFlag},
+			Action: backupAction,
+		},
+		{
+			Name:   "replay",
+			Usage:  "replays a range of blocks from a copy of the chain database and reports state divergences",
+			Flags:  []cli.Flag{ReplayFromFlag, ReplayToFlag, ReplayRuskFlag},
+			Action: replayAction,
+		},
 	}
 	app.Flags = append(app.Flags, CLIFlags...)
 	app.Flags = append(app.Flags, GlobalFlags...)
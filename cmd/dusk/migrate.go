@@ -0,0 +1,34 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"fmt"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/urfave/cli"
+)
+
+// migrateAction upgrades the chain database to the schema version supported
+// by this node. The node must not be running.
+func migrateAction(ctx *cli.Context) error {
+	if err := loadCommandConfig(ctx); err != nil {
+		return err
+	}
+
+	if cfg.Get().Database.Driver != heavy.DriverName {
+		return fmt.Errorf("migrate is not supported by driver %s", cfg.Get().Database.Driver)
+	}
+
+	if err := heavy.Migrate(cfg.Get().Database.Dir); err != nil {
+		return err
+	}
+
+	log.WithField("version", heavy.SchemaVersion).Info("chain database migrated")
+	return nil
+}
@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/urfave/cli"
+)
+
+// replayAction replays a range of blocks of the chain database against a Rusk
+// service, and prints a JSON report of the divergences to stdout. The database
+// is opened in read-only mode, and should be a copy of the one of the node
+// under investigation. The Rusk state is modified by the replay: it must be the
+// state of the block preceding the range, and not the one of a running node.
+func replayAction(ctx *cli.Context) error {
+	if err := loadCommandConfig(ctx); err != nil {
+		return err
+	}
+
+	from := ctx.Uint64(ReplayFromFlag.Name)
+	to := ctx.Uint64(ReplayToFlag.Name)
+
+	drvr, err := database.From(cfg.Get().Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	db, err := drvr.Open(cfg.Get().Database.Dir, true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = drvr.Close()
+	}()
+
+	// By default, replay up to the chain tip
+	if to == 0 {
+		if err = db.View(func(t database.Transaction) error {
+			var e error
+			to, e = t.FetchCurrentHeight()
+			return e
+		}); err != nil {
+			return err
+		}
+	}
+
+	addr := ctx.String(ReplayRuskFlag.Name)
+	if addr == "" {
+		addr = cfg.Get().RPC.Rusk.Address
+	}
+
+	gctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Get().RPC.Rusk.ConnectionTimeout)*time.Millisecond)
+	defer cancel()
+
+	proxy, ruskConn := setupRuskClients(gctx, addr)
+
+	defer func() {
+		_ = ruskConn.Close()
+	}()
+
+	report, err := chain.Replay(context.Background(), db, proxy.Executor(), from, to)
+	if report != nil {
+		out, e := json.MarshalIndent(report, "", "  ")
+		if e != nil {
+			return e
+		}
+
+		if _, e = fmt.Fprintln(os.Stdout, string(out)); e != nil {
+			return e
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(report.Divergences) > 0 {
+		return fmt.Errorf("%d divergences found", len(report.Divergences))
+	}
+
+	return nil
+}
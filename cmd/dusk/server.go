@@ -18,12 +18,16 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+
+	// Register the bolt driver to make it selectable in the configuration.
+	_ "github.com/dusk-network/dusk-blockchain/pkg/core/database/bolt"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/loop"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/dusk-network/dusk-blockchain/pkg/gql"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/kadcast"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/responding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/rpc/client"
@@ -46,6 +50,7 @@ type Server struct {
 	readerFactory *peer.ReaderFactory
 	kadPeer       *kadcast.Peer
 
+	mempool  *mempool.Mempool
 	dbDriver database.Driver
 
 	// Parent context to all long-lived goroutines triggered by any subsystem.
@@ -96,6 +101,9 @@ func Setup() *Server {
 	processor := peer.NewMessageProcessor(eventBus)
 	registerPeerServices(processor, db, eventBus, rpcBus)
 
+	// Misbehaving peers are reported to the scoring service
+	scoring.NewKeeper(eventBus)
+
 	// Instantiate gRPC client
 	// TODO: get address from config
 	gctx, cancel := context.WithTimeout(parentCtx, time.Duration(cfg.Get().RPC.Rusk.ConnectionTimeout)*time.Millisecond)
@@ -144,6 +152,7 @@ func Setup() *Server {
 	}
 
 	processor.Register(topics.Block, c.ProcessBlockFromNetwork)
+	processor.Register(topics.Headers, c.ProcessHeadersFromNetwork)
 
 	// Instantiate GraphQL server
 	var gqlServer *gql.Server
@@ -175,6 +184,7 @@ func Setup() *Server {
 		grpcServer:    nil,
 		ruskConn:      ruskConn,
 		readerFactory: readerFactory,
+		mempool:       m,
 		dbDriver:      driver,
 		ctx:           parentCtx,
 		cancel:        parentCancel,
@@ -207,6 +217,11 @@ func (s *Server) Close() {
 	// Cancel all goroutines long-lived loops
 	s.cancel()
 
+	// Wait for the mempool txs to be dumped before the process exits
+	if s.mempool != nil {
+		s.mempool.Wait()
+	}
+
 	// Close graphql server.
 	if s.gqlServer != nil {
 		if err := s.gqlServer.Close(); err != nil {
@@ -250,6 +265,8 @@ func registerPeerServices(processor *peer.MessageProcessor, db database.DB, even
 	processor.Register(topics.Pong, responding.ProcessPong)
 	processor.Register(topics.Inv, dataRequestor.RequestMissingItems)
 	processor.Register(topics.GetBlocks, bhb.AdvertiseMissingBlocks)
+	processor.Register(topics.GetBlockRange, bhb.AdvertiseBlockRange)
+	processor.Register(topics.GetHeaders, bhb.ProvideHeaders)
 	processor.Register(topics.GetCandidate, cb.ProvideCandidate)
 	processor.Register(topics.NewBlock, cp.Process)
 	processor.Register(topics.Reduction, cp.Process)
@@ -259,9 +276,15 @@ func registerPeerServices(processor *peer.MessageProcessor, db database.DB, even
 }
 
 func setupGRPCClients(ctx context.Context) (transactions.Proxy, *grpc.ClientConn) {
-	addr := cfg.Get().RPC.Rusk.Address
+	return setupRuskClients(ctx, cfg.Get().RPC.Rusk.Address)
+}
+
+// setupRuskClients connects to the Rusk service at the given address, with the
+// configured network and timeouts.
+func setupRuskClients(ctx context.Context, address string) (transactions.Proxy, *grpc.ClientConn) {
+	addr := address
 	if cfg.Get().RPC.Rusk.Network == "unix" {
-		addr = "unix://" + cfg.Get().RPC.Rusk.Address
+		addr = "unix://" + address
 	}
 
 	ruskClient, ruskConn := client.CreateStateClient(ctx, addr)
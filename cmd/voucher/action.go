@@ -15,6 +15,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/cmd/voucher/node"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/responding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
@@ -40,9 +41,12 @@ func action(ctx *cli.Context) error {
 	}
 
 	store := node.NewStore()
-	challenger := challenger.New(store)
+	challenger := challenger.New(store, eb)
 	processor := peer.NewMessageProcessor(eb)
 
+	// Peers failing the challenge are banned by the scoring service
+	scoring.NewKeeper(eb)
+
 	processor.Register(topics.Response, challenger.ProcessResponse)
 	processor.Register(topics.GetAddrs, store.DumpNodes)
 	processor.Register(topics.Ping, responding.ProcessPing)
@@ -16,9 +16,11 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/cmd/voucher/node"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -26,13 +28,15 @@ const challengeLength = 20
 
 // Challenger is the component responsible for vetting incoming connections.
 type Challenger struct {
-	nodes  *node.Store
-	gossip *protocol.Gossip
+	nodes     *node.Store
+	gossip    *protocol.Gossip
+	publisher eventbus.Publisher
 }
 
-// New creates a new, initialized Challenger.
-func New(store *node.Store) *Challenger {
-	return &Challenger{nodes: store, gossip: protocol.NewGossip()}
+// New creates a new, initialized Challenger. Peers failing the challenge are
+// reported to the scoring service through the publisher.
+func New(store *node.Store, publisher eventbus.Publisher) *Challenger {
+	return &Challenger{nodes: store, gossip: protocol.NewGossip(), publisher: publisher}
 }
 
 // SendChallenge to a connecting peer.
@@ -72,6 +76,7 @@ func (c *Challenger) ProcessResponse(srcPeerID string, m message.Message) ([]byt
 
 	if !hashesMatch(resp.HashedChallenge, node.Challenge) {
 		c.nodes.BlackList(srcPeerID)
+		scoring.Report(c.publisher, srcPeerID, scoring.InvalidChallengeResponse, "invalid challenge response")
 		return nil, errors.New("received invalid response")
 	}
 
@@ -15,6 +15,10 @@ const (
 	// This value should handle up to ~20 transfers (or ~5 InterContract calls) per block.
 	DefaultBlockGasLimit = 5 * DUSK
 
+	// DefaultCandidateExpiry is the default number of rounds candidate blocks
+	// are kept behind the chain tip.
+	DefaultCandidateExpiry = 3
+
 	// MaxTxSetSize defines the maximum amount of transactions.
 	// It is TBD along with block size and processing.MaxFrameSize.
 	MaxTxSetSize = 825000
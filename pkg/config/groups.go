@@ -7,7 +7,15 @@
 package config
 
 type generalConfiguration struct {
-	Network string
+	Network     string
+	Checkpoints []checkpointConfiguration
+}
+
+// checkpointConfiguration is a block known to be part of the network chain.
+type checkpointConfiguration struct {
+	Height uint64
+	// Hash is the hex encoded block hash.
+	Hash string
 }
 
 type timeoutConfiguration struct {
@@ -26,6 +34,11 @@ type networkConfiguration struct {
 	MaxDupeMapExpire uint32
 
 	ServiceFlag uint8
+
+	// Misbehavior score above which a peer is banned.
+	BanThreshold uint32
+	// Number of seconds a banned peer is refused.
+	BanCooldown uint32
 }
 
 type clientConfiguration struct {
@@ -46,6 +59,27 @@ type kadcastConfiguration struct {
 type databaseConfiguration struct {
 	Driver string
 	Dir    string
+
+	// PruneDepth enables pruning mode if greater than 0. Transactions payload
+	// of blocks older than PruneDepth blocks behind the persisted block is
+	// deleted. Headers, certificates and the genesis block are kept.
+	PruneDepth uint64
+
+	// IndexNullifiers enables the index of confirmed transactions by their
+	// nullifiers.
+	//
+	// Secondary indices are updated only while enabled. Enabling an index does
+	// not backfill the blocks already stored, and blocks deleted while it is
+	// disabled (e.g. on a chain revert) leave stale records behind. A full
+	// resync is needed to get a consistent index in both cases.
+	IndexNullifiers bool
+	// IndexContracts enables the index of confirmed transactions by the
+	// contract they call. See IndexNullifiers.
+	IndexContracts bool
+
+	// CandidateExpiry is the number of rounds candidate blocks are kept
+	// behind the chain tip.
+	CandidateExpiry uint64
 }
 
 // pprof configs.
@@ -129,11 +163,25 @@ type mempoolConfiguration struct {
 	PropagateTimeout string
 	PropagateBurst   uint32
 
+	// Minimum increase of GasPrice, in percent, for a tx to replace the pooled
+	// txs spending the same nullifiers
+	ReplaceByFeeMargin uint32
+
+	// Time to live of a tx in the mempool. Empty disables expiry
+	TxTTL string
+	// Max number of times a tx is dropped by Rusk from a candidate block. 0
+	// disables the limit
+	MaxInclusionAttempts uint32
+
 	// diskpool config
 	DiskPoolDir string
 
 	// Hashmap config
 	HashMapPreallocTxs uint32
+	// File the hashmap txs are dumped into on close and reloaded from at
+	// start-up. A relative path is resolved under Database.Dir. Empty
+	// disables it
+	HashMapDumpFile string
 
 	// Enables mempool updates at startup
 	Updates updates
@@ -150,6 +150,7 @@ func (r *Registry) init(secondary interface{}) error {
 	}
 
 	defineENV()
+	defineDefaults()
 
 	// Uncomment on debugging only. This will list all levels of configurations
 	// viper.Debug()
@@ -232,6 +233,11 @@ func defineENV() {
 	}
 }
 
+// define default values of the settings missing in the config file.
+func defineDefaults() {
+	viper.SetDefault("database.candidateExpiry", DefaultCandidateExpiry)
+}
+
 // Mock should be used only in test packages. It could be useful when a unit
 // test needs to be rerun with configs different from the default ones.
 func Mock(m *Registry) {
@@ -257,4 +263,5 @@ func init() {
 	r.Mempool.ExtractionDelaySecs = 3
 	r.State.PersistEvery = 1
 	r.State.BlockGasLimit = DefaultBlockGasLimit
+	r.Database.CandidateExpiry = DefaultCandidateExpiry
 }
@@ -168,6 +168,22 @@ func TestSecondaryRegistry(t *testing.T) {
 	}
 }
 
+// TestDefaults ensures settings missing in the config file are set to their
+// default values.
+func TestDefaults(t *testing.T) {
+	Reset()
+
+	// custom.toml does not set database.candidateExpiry
+	r, err := LoadFromFile("./samples/custom.toml")
+	if err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+
+	if r.Database.CandidateExpiry != DefaultCandidateExpiry {
+		t.Errorf("Invalid CandidateExpiry value: %d", r.Database.CandidateExpiry)
+	}
+}
+
 func Reset() {
 	pflag.CommandLine = &pflag.FlagSet{}
 	pflag.Usage = func() {}
@@ -0,0 +1,170 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+)
+
+// An archive is a portable file of consecutive blocks. It consists of a
+// header (magic + version) followed by a list of records.
+//
+// Record schema:
+//
+// | Length (uint32 LE) | message.MarshalBlock() | Checksum |
+//
+// Length denotes the size of the encoded block. Checksum is generated by
+// wire/checksum over the encoded block.
+
+const (
+	// Version of the archive format.
+	Version uint8 = 1
+
+	// maxRecordSize protects the reader from allocating huge buffers on a
+	// corrupted length field.
+	maxRecordSize = 250 * 1024 * 1024
+)
+
+var (
+	magic = []byte("DUSKCHAIN")
+
+	// ErrInvalidArchive is returned when the archive header is not recognized.
+	ErrInvalidArchive = errors.New("archive: invalid archive header")
+	// ErrInvalidChecksum is returned when a record does not match its checksum.
+	ErrInvalidChecksum = errors.New("archive: invalid record checksum")
+)
+
+// Writer encodes blocks into an archive.
+type Writer struct {
+	w             io.Writer
+	headerWritten bool
+}
+
+// NewWriter returns a Writer that writes an archive into w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteBlock appends a block record to the archive.
+func (a *Writer) WriteBlock(b *block.Block) error {
+	if !a.headerWritten {
+		header := make([]byte, 0, len(magic)+1)
+		header = append(append(header, magic...), Version)
+
+		if _, err := a.w.Write(header); err != nil {
+			return err
+		}
+
+		a.headerWritten = true
+	}
+
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, b); err != nil {
+		return err
+	}
+
+	payload := buf.Bytes()
+
+	lenBytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(lenBytes, uint32(len(payload)))
+
+	if _, err := a.w.Write(lenBytes); err != nil {
+		return err
+	}
+
+	if _, err := a.w.Write(payload); err != nil {
+		return err
+	}
+
+	_, err := a.w.Write(checksum.Generate(payload))
+	return err
+}
+
+// Reader decodes blocks from an archive.
+type Reader struct {
+	r            io.Reader
+	headerParsed bool
+}
+
+// NewReader returns a Reader that reads an archive from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadBlock reads the next block record of the archive. It returns io.EOF
+// when there are no more records.
+func (a *Reader) ReadBlock() (*block.Block, error) {
+	if !a.headerParsed {
+		if err := a.readHeader(); err != nil {
+			return nil, err
+		}
+
+		a.headerParsed = true
+	}
+
+	lenBytes := make([]byte, 4)
+	if _, err := io.ReadFull(a.r, lenBytes); err != nil {
+		// io.EOF is returned only if no bytes were read, which means a
+		// complete archive.
+		return nil, err
+	}
+
+	length := binary.LittleEndian.Uint32(lenBytes)
+	if length > maxRecordSize {
+		return nil, fmt.Errorf("archive: invalid record length %d", length)
+	}
+
+	record := make([]byte, int(length)+checksum.Length)
+	if _, err := io.ReadFull(a.r, record); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	payload, cs := record[:length], record[length:]
+	if !checksum.Verify(payload, cs) {
+		return nil, ErrInvalidChecksum
+	}
+
+	b := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(payload), b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (a *Reader) readHeader() error {
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(a.r, header); err != nil {
+		return unexpectedEOF(err)
+	}
+
+	if !bytes.Equal(header[:len(magic)], magic) {
+		return ErrInvalidArchive
+	}
+
+	if header[len(magic)] != Version {
+		return fmt.Errorf("archive: unsupported version %d", header[len(magic)])
+	}
+
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package archive_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/chain/archive"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundtrip(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	blocks := make([]*block.Block, 0)
+	w := archive.NewWriter(buf)
+
+	for i := uint64(1); i <= 5; i++ {
+		blk := helper.RandomBlock(i, 1)
+		assert.NoError(w.WriteBlock(blk))
+
+		blocks = append(blocks, blk)
+	}
+
+	r := archive.NewReader(buf)
+
+	for _, blk := range blocks {
+		decoded, err := r.ReadBlock()
+		assert.NoError(err)
+		assert.True(blk.Equals(decoded))
+	}
+
+	_, err := r.ReadBlock()
+	assert.Equal(io.EOF, err)
+}
+
+func TestArchiveCorrupted(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	assert.NoError(archive.NewWriter(buf).WriteBlock(helper.RandomBlock(1, 1)))
+
+	// Flip the last byte of the encoded block
+	data := buf.Bytes()
+	data[len(data)-5] ^= 0xff
+
+	_, err := archive.NewReader(bytes.NewBuffer(data)).ReadBlock()
+	assert.Equal(archive.ErrInvalidChecksum, err)
+
+	// Unknown header
+	_, err = archive.NewReader(bytes.NewBufferString("not an archive file")).ReadBlock()
+	assert.Equal(archive.ErrInvalidArchive, err)
+}
@@ -0,0 +1,145 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Backup streams an online backup of the chain database. See package backup
+// for the archive layout.
+//
+// The snapshot is taken while holding the chain lock so that it matches the
+// Rusk state root. The lock is released before archiving, so the node keeps
+// accepting blocks in the meantime.
+func (c *Chain) Backup(_ *emptypb.Empty, stream backup.Backup_BackupServer) error {
+	s, ok := c.db.(database.Snapshotter)
+	if !ok {
+		return errors.New("database driver does not support online backups")
+	}
+
+	c.lock.RLock()
+
+	snapshot, err := s.Snapshot()
+	if err != nil {
+		c.lock.RUnlock()
+		return err
+	}
+
+	defer snapshot.Close()
+
+	stateRoot, err := c.proxy.Executor().GetStateRoot(c.ctx)
+
+	c.lock.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backupManifest(snapshot, stateRoot)
+	if err != nil {
+		return err
+	}
+
+	l := log.WithField("event", "backup").
+		WithField("tip_h", manifest.TipHeight).
+		WithField("persisted_h", manifest.PersistedHeight)
+
+	l.Info("backup started")
+
+	w := backup.NewWriter(stream)
+	tw := tar.NewWriter(w)
+
+	if err := writeManifest(tw, manifest); err != nil {
+		return err
+	}
+
+	if err := snapshot.Archive(tw, backup.ChainDir); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	l.Info("backup completed")
+	return nil
+}
+
+// backupManifest builds the manifest of a snapshot. Rusk state root must match
+// the state hash of the tip block, as Rusk is at the tip state at runtime. The
+// state hash of the persisted block is recorded too, since Rusk is expected to
+// be at the persisted state when the node is started.
+func backupManifest(t database.Transaction, stateRoot []byte) (*backup.Manifest, error) {
+	r, err := t.FetchRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	var tip, persisted *block.Header
+
+	if tip, err = t.FetchBlockHeader(r.TipHash); err != nil {
+		return nil, err
+	}
+
+	if persisted, err = t.FetchBlockHeader(r.PersistedHash); err != nil {
+		return nil, err
+	}
+
+	if tip.Height > 0 && !bytes.Equal(tip.StateHash, stateRoot) {
+		log.WithField("rusk", hex.EncodeToString(stateRoot)).
+			WithField("node", hex.EncodeToString(tip.StateHash)).
+			Error("backup failed")
+		return nil, errInvalidStateHash
+	}
+
+	return &backup.Manifest{
+		Driver:             config.Get().Database.Driver,
+		TipHash:            hex.EncodeToString(tip.Hash),
+		TipHeight:          tip.Height,
+		PersistedHash:      hex.EncodeToString(persisted.Hash),
+		PersistedHeight:    persisted.Height,
+		StateRoot:          hex.EncodeToString(stateRoot),
+		PersistedStateRoot: hex.EncodeToString(persisted.StateHash),
+	}, nil
+}
+
+func writeManifest(tw *tar.Writer, m *backup.Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Name:    backup.ManifestName,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+	return err
+}
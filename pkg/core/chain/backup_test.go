@@ -0,0 +1,135 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
+	assert "github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestBackup(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "backup_test_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	drvr, err := database.From(heavy.DriverName)
+	assert.NoError(err)
+
+	db, err := drvr.Open(dir, false)
+	assert.NoError(err)
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	// Persisted block followed by a tentative one, each with its own state
+	persisted := helper.RandomBlock(1, 1)
+	persisted.Header.StateHash = bytes.Repeat([]byte{1}, 32)
+
+	tip := helper.RandomBlock(2, 1)
+	tip.Header.StateHash = make([]byte, 32)
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		if err := t.StoreBlock(persisted, true); err != nil {
+			return err
+		}
+
+		return t.StoreBlock(tip, false)
+	}))
+
+	c := &Chain{
+		db:    db,
+		ctx:   context.Background(),
+		proxy: &transactions.MockProxy{E: transactions.MockExecutor(0)},
+	}
+
+	// Rusk is at the tip state
+	stream := new(backupStream)
+	assert.NoError(c.Backup(&emptypb.Empty{}, stream))
+
+	var (
+		m     backup.Manifest
+		files int
+	)
+
+	tr := tar.NewReader(&stream.buf)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		assert.NoError(err)
+
+		switch {
+		case hdr.Name == backup.ManifestName:
+			assert.NoError(json.NewDecoder(tr).Decode(&m))
+		case strings.HasPrefix(hdr.Name, backup.ChainDir+"/"):
+			files++
+		}
+	}
+
+	assert.NotZero(files)
+	assert.Equal(hex.EncodeToString(tip.Header.Hash), m.TipHash)
+	assert.Equal(uint64(2), m.TipHeight)
+	assert.Equal(hex.EncodeToString(persisted.Header.Hash), m.PersistedHash)
+	assert.Equal(uint64(1), m.PersistedHeight)
+	assert.Equal(hex.EncodeToString(tip.Header.StateHash), m.StateRoot)
+	assert.Equal(hex.EncodeToString(persisted.Header.StateHash), m.PersistedStateRoot)
+
+	// Rusk is not at the tip state
+	c.proxy = &transactions.MockProxy{E: &rootExecutor{
+		PermissiveExecutor: transactions.MockExecutor(0),
+		root:               []byte{2},
+	}}
+
+	assert.Equal(errInvalidStateHash, c.Backup(&emptypb.Empty{}, new(backupStream)))
+}
+
+// backupStream collects the chunks of a backup archive.
+type backupStream struct {
+	grpc.ServerStream
+	buf bytes.Buffer
+}
+
+func (s *backupStream) Send(m *backup.BackupChunk) error {
+	_, err := s.buf.Write(m.Data)
+	return err
+}
+
+// rootExecutor reports a fixed Rusk state root.
+type rootExecutor struct {
+	*transactions.PermissiveExecutor
+	root []byte
+}
+
+func (e *rootExecutor) GetStateRoot(context.Context) ([]byte, error) {
+	return e.root, nil
+}
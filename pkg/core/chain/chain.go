@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
@@ -26,8 +27,11 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/loop"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/dupemap"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/syncprogress"
 	"github.com/dusk-network/dusk-blockchain/pkg/util"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/diagnostics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
@@ -40,8 +44,14 @@ import (
 )
 
 var (
+	// checkBlockCertificate verifies the certificate of a block. Replaced by
+	// tests only.
+	checkBlockCertificate = agreement.CheckBlockCertificate
+
 	errInvalidStateHash    = errors.New("invalid state hash")
 	errUnexpectedStateHash = errors.New("unexpected state hash")
+	errNotGenesisState     = errors.New("contract storage is not at the genesis state, reset rusk first")
+	errConflictingBlock    = errors.New("block conflicts with the accepted one at the same height")
 
 	log = logger.WithFields(logger.Fields{"process": "chain"})
 )
@@ -49,6 +59,28 @@ var (
 // ErrBlockAlreadyAccepted block already known by blockchain state.
 var ErrBlockAlreadyAccepted = errors.New("already accepted")
 
+// invalidBlockError marks the failure of a block to pass verification, as
+// opposed to a failure of the node itself (e.g. Rusk or database errors).
+type invalidBlockError struct {
+	err error
+}
+
+func (e invalidBlockError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidBlockError) Unwrap() error {
+	return e.err
+}
+
+// isInvalidBlock returns true if err results from an invalid block, i.e. a
+// block failing the hash, sanity or certificate checks. Only invalid blocks
+// are held against the peer which provided them.
+func isInvalidBlock(err error) bool {
+	var e invalidBlockError
+	return errors.As(err, &e)
+}
+
 // TODO: This Verifier/Loader interface needs to be re-evaluated and most likely
 // renamed. They don't make too much sense on their own (the `Loader` also
 // appends blocks, and allows for fetching data from the DB), and potentially
@@ -101,10 +133,13 @@ type Chain struct {
 	loop              *loop.Consensus
 	stopConsensusChan chan struct{}
 	loopID            uint64
+	// runningLoopID is the ID of the running consensus loop, 0 if none.
+	runningLoopID uint64
 
 	// Syncing related things.
 	*synchronizer
 	highestSeen uint64
+	monitor     syncMonitor
 
 	// rusk client.
 	proxy transactions.Proxy
@@ -113,6 +148,12 @@ type Chain struct {
 
 	blacklisted dupemap.TmpMap
 	verified    sortedset.SafeSet
+
+	// Blocks from competing branches.
+	forks *forkTracker
+
+	// Blocks known to be part of the network chain.
+	checkpoints checkpoints
 }
 
 // New returns a new chain object. It accepts the EventBus (for messages coming
@@ -132,9 +173,18 @@ func New(ctx context.Context, db database.DB, eventBus *eventbus.EventBus, rpcBu
 		stopConsensusChan: make(chan struct{}),
 		blacklisted:       *dupemap.NewTmpMap(1000, 120),
 		verified:          sortedset.NewSafeSet(),
+		forks:             newForkTracker(),
 	}
 
-	chain.synchronizer = newSynchronizer(db, chain)
+	chain.synchronizer = newSynchronizer(db, chain, eventBus)
+
+	cp, err := loadCheckpoints()
+	if err != nil {
+		log.WithError(err).Error("Error in loading checkpoints")
+		return nil, err
+	}
+
+	chain.checkpoints = cp
 
 	provisioners, err := proxy.Executor().GetProvisioners(ctx)
 	if err != nil {
@@ -144,6 +194,8 @@ func New(ctx context.Context, db database.DB, eventBus *eventbus.EventBus, rpcBu
 
 	if srv != nil {
 		node.RegisterChainServer(srv, chain)
+		backup.RegisterBackupServer(srv, chain)
+		syncprogress.RegisterSyncProgressServer(srv, chain)
 	}
 
 	chain.p = &provisioners
@@ -161,8 +213,13 @@ func (c *Chain) syncWithRusk() error {
 		ruskStateHash []byte
 		persistedHash []byte
 		prevBlock     *block.Block
+		journaled     *block.Block
 	)
 
+	if journaled, err = c.resolveJournal(); err != nil {
+		return err
+	}
+
 	ruskStateHash, err = c.proxy.Executor().GetStateRoot(c.ctx)
 	if err != nil {
 		return err
@@ -203,7 +260,7 @@ func (c *Chain) syncWithRusk() error {
 	// If both persisted block hash and latest blockchain block hash are the
 	// same then there is no need to execute sync-up.
 	if bytes.Equal(persistedHash, prevBlock.Header.Hash) {
-		return nil
+		return c.replayJournal(journaled)
 	}
 
 	// re-accept missing block in order to recover Rusk (unpersisted) state.
@@ -237,6 +294,140 @@ func (c *Chain) syncWithRusk() error {
 		}
 	}
 
+	return c.replayJournal(journaled)
+}
+
+// resolveJournal resolves a block acceptance interrupted by a crash, if any.
+// If Rusk already holds the state of the journaled block, the block is stored
+// without executing its state transition again. Otherwise, Rusk is brought
+// back to the persisted state, whatever the progress of the interrupted state
+// transition, so that the unpersisted blocks can be re-accepted on top of it.
+// The journaled block is then returned if it was not stored, so that it can be
+// replayed afterwards.
+func (c *Chain) resolveJournal() (*block.Block, error) {
+	var blk *block.Block
+
+	err := c.db.View(func(t database.Transaction) error {
+		var err error
+		blk, err = t.FetchJournal()
+		return err
+	})
+
+	if err == database.ErrJournalEmpty {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	l := log.WithField("event", "resolve_journal").
+		WithField("height", blk.Header.Height).
+		WithField("hash", util.StringifyBytes(blk.Header.Hash))
+
+	tip, persistedHash, err := c.loader.LoadTip()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stored    bool
+		persisted *block.Block
+	)
+
+	err = c.db.View(func(t database.Transaction) error {
+		stored, _ = t.FetchBlockExists(blk.Header.Hash)
+
+		var err error
+		persisted, err = t.FetchBlock(persistedHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ruskStateHash, err := c.proxy.Executor().GetStateRoot(c.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l = l.WithField("rusk", hex.EncodeToString(ruskStateHash)).
+		WithField("persisted_h", persisted.Header.Height)
+
+	// The journaled block can be stored as is if its state transition was
+	// finalized by Rusk. This happens on a crash after Rusk persisted the
+	// block state but before the block was committed to the database.
+	storable := !stored && isPersistHeight(blk.Header.Height) &&
+		bytes.Equal(blk.Header.PrevBlockHash, tip.Header.Hash)
+
+	switch {
+	case bytes.Equal(ruskStateHash, persisted.Header.StateHash):
+		l.Info("contract storage at persisted state")
+	case storable && bytes.Equal(ruskStateHash, blk.Header.StateHash):
+		l.Info("contract storage at journaled block state")
+		return nil, c.persist(blk)
+	case bytes.Equal(ruskStateHash, tip.Header.StateHash), bytes.Equal(ruskStateHash, blk.Header.StateHash):
+		// Rusk is ahead of the persisted state, either at the tip state or
+		// at the state of the journaled block.
+		l.Info("revert contract storage")
+
+		stateHash, err := c.proxy.Executor().Revert(c.ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if storable && bytes.Equal(stateHash, blk.Header.StateHash) {
+			l.Info("contract storage finalized at journaled block state")
+			return nil, c.persist(blk)
+		}
+
+		if persisted.Header.Height > 0 && !bytes.Equal(stateHash, persisted.Header.StateHash) {
+			l.WithField("reverted", hex.EncodeToString(stateHash)).
+				WithError(errInvalidStateHash).
+				Error("reverted state does not match the persisted block")
+			return nil, errInvalidStateHash
+		}
+	case persisted.Header.Height == 0:
+		// Genesis state hash is not checked against Rusk. See syncWithRusk.
+		l.Info("contract storage at genesis state")
+	default:
+		l.WithError(errInvalidStateHash).Error("unknown contract storage state")
+		return nil, errInvalidStateHash
+	}
+
+	if err = c.db.Update(func(t database.Transaction) error {
+		return t.DeleteJournal()
+	}); err != nil {
+		return nil, err
+	}
+
+	if stored {
+		l.Info("block already stored")
+		return nil, nil
+	}
+
+	return blk, nil
+}
+
+// replayJournal re-accepts the block of an interrupted acceptance, if it
+// follows the chain tip. Its state transition is executed again, as any
+// tentative state of the block has been dropped by resolveJournal.
+func (c *Chain) replayJournal(blk *block.Block) error {
+	if blk == nil || !bytes.Equal(blk.Header.PrevBlockHash, c.tip.Header.Hash) {
+		return nil
+	}
+
+	l := log.WithField("event", "resolve_journal").
+		WithField("height", blk.Header.Height).
+		WithField("hash", util.StringifyBytes(blk.Header.Hash))
+
+	l.Info("replay block")
+
+	// The block is received again from the network if it cannot be replayed
+	if err := c.acceptBlock(*blk, false); err != nil {
+		l.WithError(err).Warn("replaying block failed")
+	}
+
 	return nil
 }
 
@@ -250,12 +441,22 @@ func (c *Chain) ProcessBlockFromNetwork(srcPeerID string, m message.Message) ([]
 
 	// Ensure the received block provides a valid hash
 	if err := verifiers.CheckHash(&blk); err != nil {
+		scoring.Report(c.eventBus, srcPeerID, scoring.InvalidBlock, err.Error())
+		return nil, err
+	}
+
+	// Reject any block from a branch conflicting with a checkpoint
+	if err := c.checkpoints.check(blk.Header); err != nil {
+		scoring.Report(c.eventBus, srcPeerID, scoring.InvalidBlock, err.Error())
 		return nil, err
 	}
 
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	// The block may change the synchronizer state
+	defer c.publishSyncProgress()
+
 	l := log.WithField("recv_blk_h", blk.Header.Height).
 		WithField("curr_h", c.tip.Header.Height)
 
@@ -273,6 +474,18 @@ func (c *Chain) ProcessBlockFromNetwork(srcPeerID string, m message.Message) ([]
 	}
 
 	switch {
+	case c.forks.has(blk.Header.PrevBlockHash),
+		blk.Header.Height == c.tip.Header.Height && !bytes.Equal(blk.Header.PrevBlockHash, c.tip.Header.PrevBlockHash):
+		// Block from a competing branch
+		if err := c.processForkBlock(blk, l); err != nil {
+			l.WithError(err).Warn("invalid fork block")
+
+			if isInvalidBlock(err) {
+				scoring.Report(c.eventBus, srcPeerID, scoring.InvalidBlock, err.Error())
+			}
+		}
+
+		return nil, nil
 	case blk.Header.Height == c.tip.Header.Height:
 		{
 			// Check if we already accepted this block
@@ -297,18 +510,15 @@ func (c *Chain) ProcessBlockFromNetwork(srcPeerID string, m message.Message) ([]
 			return c.synchronizer.processBlock(srcPeerID, c.tip.Header.Height, blk, m.Metadata())
 		}
 	case blk.Header.Height < c.tip.Header.Height:
-		l.Debug("discard block")
-
 		// Due to a network glitch, the fallback procedure may be skipped.
-		// In this case, network may continue on a branch with higher cert_step value.
-		// Here we try to detect the above edge case.
-		if res, err := c.isBlockFromFork(blk); err != nil {
-			l.WithError(err).Warn("invalid block")
-		} else {
-			if res {
-				l.WithField("recv_blk_step", blk.Header.Certificate.Step).
-					WithField("recv_blk_hash", hex.EncodeToString(h)).
-					WithField("event", "fallback").Error("fork detected")
+		// In this case, network may continue on a branch with lower
+		// cert_step values. Such a branch is tracked, and the chain is
+		// reorganized once it is found better than the local one.
+		if err := c.processForkBlock(blk, l); err != nil {
+			l.WithError(err).Warn("invalid fork block")
+
+			if isInvalidBlock(err) {
+				scoring.Report(c.eventBus, srcPeerID, scoring.InvalidBlock, err.Error())
 			}
 		}
 
@@ -329,6 +539,37 @@ func (c *Chain) TryNextConsecutiveBlockOutSync(blk block.Block, metadata *messag
 	return c.acceptBlock(blk, true)
 }
 
+// ImportBlock is the processing path for accepting a block read from a chain
+// archive. It performs the same checks as the network sync path.
+//
+// A block at or below the chain tip results in ErrBlockAlreadyAccepted if it
+// matches the accepted block at its height, or in an error otherwise.
+func (c *Chain) ImportBlock(blk block.Block) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if blk.Header.Height <= c.tip.Header.Height {
+		var hash []byte
+
+		if err := c.db.View(func(t database.Transaction) error {
+			var err error
+			hash, err = t.FetchBlockHashByHeight(blk.Header.Height)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if !bytes.Equal(hash, blk.Header.Hash) {
+			return errConflictingBlock
+		}
+
+		return ErrBlockAlreadyAccepted
+	}
+
+	log.WithField("height", blk.Header.Height).Trace("accepting imported block")
+	return c.acceptBlock(blk, true)
+}
+
 // TryNextConsecutiveBlockInSync is the processing path for accepting a block
 // from the network during in-sync state. Returns err if the block is not valid.
 func (c *Chain) TryNextConsecutiveBlockInSync(blk block.Block, metadata *message.Metadata) error {
@@ -365,22 +606,31 @@ func (c *Chain) TryNextConsecutiveBlockIsValid(blk block.Block) error {
 }
 
 // ProcessSyncTimerExpired called by outsync timer when a peer does not provide GetData response.
-// It implements transition back to inSync state.
-// strPeerAddr is the address of the peer initiated the syncing but failed to deliver.
+// The headers or ranges requested from the peer are requested from other
+// peers. If it fails, it implements transition back to inSync state.
+// strPeerAddr is the address of the peer requested for a range but failed to deliver.
 func (c *Chain) ProcessSyncTimerExpired(strPeerAddr string) error {
 	log.WithField("curr", c.tip.Header.Height).
 		WithField("src_addr", strPeerAddr).Warn("sync timer expired")
 
+	scoring.Report(c.eventBus, strPeerAddr, scoring.SyncTimeout, "sync timer expired")
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	err := c.synchronizer.retryRanges(strPeerAddr, c.tip.Header.Height)
+	if err == nil {
+		return nil
+	}
+
+	log.WithError(err).Warn("could not request sync ranges again")
+
 	if err := c.RestartConsensus(); err != nil {
 		log.WithError(err).Warn("sync timer could not restart consensus loop")
 	}
 
-	log.WithField("state", "inSync").Traceln("change sync state")
-
-	c.state = c.inSync
+	c.synchronizer.dropSync()
+	c.publishSyncProgress()
 	return nil
 }
 
@@ -504,6 +754,8 @@ func (c *Chain) runStateTransition(tipBlk, blk block.Block) (*block.Block, error
 			WithField("node", util.StringifyBytes(blk.Header.StateHash)).
 			WithError(errInvalidStateHash).Error("inconsistency with state_hash")
 
+		// A state hash mismatch may result from a local Rusk inconsistency as
+		// well, so it is not held against the peer which provided the block.
 		return block.NewBlock(), errInvalidStateHash
 	}
 
@@ -576,7 +828,7 @@ func (c *Chain) isValidHeader(newBlock, prevBlock block.Block, provisioners user
 	if withSanityCheck {
 		if err := c.verifier.SanityCheckBlock(prevBlock, newBlock); err != nil {
 			l.WithError(err).Error("block header verification failed")
-			return err
+			return invalidBlockError{err}
 		}
 	}
 
@@ -587,9 +839,9 @@ func (c *Chain) isValidHeader(newBlock, prevBlock block.Block, provisioners user
 	l.Debug("verifying block certificate")
 
 	var err error
-	if err = agreement.CheckBlockCertificate(provisioners, newBlock, prevBlock.Header.Seed); err != nil {
+	if err = checkBlockCertificate(provisioners, newBlock, prevBlock.Header.Seed); err != nil {
 		l.WithError(err).Error("certificate verification failed")
-		return err
+		return invalidBlockError{err}
 	}
 
 	return nil
@@ -618,15 +870,32 @@ func (c *Chain) acceptBlock(blk block.Block, withSanityCheck bool) error {
 		return err
 	}
 
-	// 2. Perform State Transition to update Contract Storage with Tentative or Finalized state.
+	// 2. Record the block being accepted so that a crash during the state
+	// transition can be resolved on startup. See resolveJournal.
+	if err = c.db.Update(func(t database.Transaction) error {
+		return t.StoreJournal(&blk)
+	}); err != nil {
+		l.WithError(err).Error("storing journal failed")
+		return err
+	}
+
+	// 3. Perform State Transition to update Contract Storage with Tentative or Finalized state.
 	var b *block.Block
 
 	if b, err = c.runStateTransition(*c.tip, blk); err != nil {
 		l.WithError(err).Error("execute state transition failed")
+
+		// The block is not accepted, there is nothing to resolve on startup
+		if derr := c.db.Update(func(t database.Transaction) error {
+			return t.DeleteJournal()
+		}); derr != nil {
+			l.WithError(derr).Warn("deleting journal failed")
+		}
+
 		return err
 	}
 
-	// 3. Persist the approved block and update in-memory chain tip
+	// 4. Persist the approved block and update in-memory chain tip
 	l.Debug("persisting block")
 
 	if err := c.persist(b); err != nil {
@@ -650,21 +919,15 @@ func (c *Chain) persist(b *block.Block) error {
 			"event":  "accept_block",
 			"height": b.Header.Height,
 			"hash":   util.StringifyBytes(b.Header.Hash),
-			"curr_h": c.tip.Header.Height,
 		})
 
 		err error
-		pe  = config.Get().State.PersistEvery
 	)
 
 	//  Atomic persist
 	err = c.db.Update(func(t database.Transaction) error {
-		var p bool
-
-		if pe > 0 && b.Header.Height%pe == 0 {
-			// Mark it as a persisted block
-			p = true
-		}
+		// Mark it as a persisted block
+		p := isPersistHeight(b.Header.Height)
 
 		// Persist block into dusk-blockchain database before any attempt to persist in Rusk.
 		// If StoreBlock fails, no change will be applied in Rusk.
@@ -673,6 +936,11 @@ func (c *Chain) persist(b *block.Block) error {
 			return err
 		}
 
+		// The block is accepted once stored
+		if err = t.DeleteJournal(); err != nil {
+			return err
+		}
+
 		// Persist Rusk state
 		if p {
 			if err = c.proxy.Executor().Persist(c.ctx, b.Header.StateHash); err != nil {
@@ -689,6 +957,12 @@ func (c *Chain) persist(b *block.Block) error {
 	return err
 }
 
+// isPersistHeight tells if the contract state is persisted at the given height.
+func isPersistHeight(height uint64) bool {
+	pe := config.Get().State.PersistEvery
+	return pe > 0 && height%pe == 0
+}
+
 // postAcceptBlock performs all post-events on accepting a block.
 func (c *Chain) postAcceptBlock(blk block.Block, l *logrus.Entry) {
 	// 1. Notify other subsystems for the accepted block
@@ -699,15 +973,24 @@ func (c *Chain) postAcceptBlock(blk block.Block, l *logrus.Entry) {
 	msg := message.New(topics.AcceptedBlock, blk)
 	errList := c.eventBus.Publish(topics.AcceptedBlock, msg)
 
-	// 2. Clear obsolete Candidate blocks
+	// 2. Delete expired Candidate blocks
 	if err := c.db.Update(func(t database.Transaction) error {
-		return t.ClearCandidateMessages()
+		expiry := config.Get().Database.CandidateExpiry
+		if blk.Header.Height < expiry {
+			return nil
+		}
+
+		return t.DeleteCandidateMessages(blk.Header.Height - expiry)
 	}); err != nil {
 		// failure here should not be treated as critical
 		l.WithError(err).Warn("candidate deletion failed")
 	}
 
 	diagnostics.LogPublishErrors("chain/chain.go, topics.AcceptedBlock", errList)
+
+	// 3. Notify the sync progress
+	c.publishSyncProgress()
+
 	l.Debug("procedure ended")
 }
 
@@ -824,10 +1107,154 @@ func (c *Chain) CalculateSyncProgress() float64 {
 
 // RebuildChain will delete all blocks except for the genesis block,
 // to allow for a full re-sync.
-// NOTE: This function no longer does anything, but is still here to conform to the
-// ChainServer interface, for GRPC communications.
+//
+// Contract Storage must be brought back to the genesis state as well. Rusk can
+// revert only to the most recent finalized state, hence once a block other
+// than genesis is finalized, Rusk has to be reset to the genesis state before
+// calling RebuildChain. This precondition is checked before anything is
+// stopped. The consensus loop is restarted only if it was running and the
+// chain was reset.
 func (c *Chain) RebuildChain(_ context.Context, e *node.EmptyRequest) (*node.GenericResponse, error) {
-	return &node.GenericResponse{Response: "Unimplemented"}, nil
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	genesis, stateHash, err := c.checkGenesisState()
+	if err != nil {
+		log.WithError(err).Error("rebuild chain failed")
+		return nil, err
+	}
+
+	running := c.isConsensusRunning()
+
+	// Consensus must not run over a state that is being wiped out.
+	c.StopConsensus()
+
+	if err = c.resetToGenesis(genesis, stateHash); err != nil {
+		log.WithError(err).Error("rebuild chain failed")
+		return nil, err
+	}
+
+	if running {
+		if err = c.RestartConsensus(); err != nil {
+			log.WithError(err).Warn("rebuild chain could not restart consensus loop")
+		}
+	}
+
+	return &node.GenericResponse{Response: "Blockchain deleted. Syncing from scratch..."}, nil
+}
+
+// checkGenesisState ensures Contract Storage is at the genesis state, or can be
+// reverted to it, i.e. the genesis block is the most recent finalized block.
+// It returns the genesis block header and the current Contract Storage state
+// hash.
+func (c *Chain) checkGenesisState() (*block.Header, []byte, error) {
+	var genesis, persisted *block.Header
+
+	// The genesis block is never pruned, still only its header is needed.
+	err := c.db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(0)
+		if err != nil {
+			return err
+		}
+
+		if genesis, err = t.FetchBlockHeader(hash); err != nil {
+			return err
+		}
+
+		s, err := t.FetchRegistry()
+		if err != nil {
+			return err
+		}
+
+		persisted, err = t.FetchBlockHeader(s.PersistedHash)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateHash, err := c.proxy.Executor().GetStateRoot(c.ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(stateHash, genesis.StateHash) && persisted.Height > 0 {
+		// Reverting would bring Contract Storage to the finalized state at
+		// persisted height, not to the genesis one.
+		return nil, nil, fmt.Errorf("%w: contract storage is finalized at height %d", errNotGenesisState, persisted.Height)
+	}
+
+	return genesis, stateHash, nil
+}
+
+// resetToGenesis brings Contract Storage to the genesis state, wipes out the
+// block store and sets the chain tip back to the genesis block.
+//
+// If Contract Storage is already at the genesis state, it is left as is.
+// Otherwise it is reverted, see checkGenesisState. If the reverted state is
+// not the genesis one, the chain is left untouched and errNotGenesisState is
+// returned.
+func (c *Chain) resetToGenesis(genesis *block.Header, stateHash []byte) error {
+	l := log.WithField("event", "rebuild_chain").
+		WithField("curr_h", c.tip.Header.Height)
+
+	var err error
+
+	if !bytes.Equal(stateHash, genesis.StateHash) {
+		l.Info("revert contract storage")
+
+		stateHash, err = c.proxy.Executor().Revert(c.ctx)
+		if err != nil {
+			return err
+		}
+
+		l.WithField("state_hash", hex.EncodeToString(stateHash)).
+			Info("revert contract storage completed")
+	}
+
+	if !bytes.Equal(stateHash, genesis.StateHash) {
+		l.WithField("genesis_state_hash", hex.EncodeToString(genesis.StateHash)).
+			WithError(errNotGenesisState).
+			Error("rebuild chain failed")
+
+		// Bring Contract Storage back to the chain tip
+		if err = c.syncWithRusk(); err != nil {
+			l.WithError(err).Error("restoring contract storage failed")
+		}
+
+		return errNotGenesisState
+	}
+
+	if err = c.loader.Clear(); err != nil {
+		return err
+	}
+
+	// On an empty DB, LoadTip stores the genesis block and returns it.
+	tip, _, err := c.loader.LoadTip()
+	if err != nil {
+		return err
+	}
+
+	provisioners, err := c.proxy.Executor().GetProvisioners(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	c.tip = tip
+	c.p = &provisioners
+	c.highestSeen = 0
+	c.verified.Reset()
+	c.forks = newForkTracker()
+	c.blacklisted = *dupemap.NewTmpMap(1000, 120)
+
+	// Restart the synchronizer so that any ongoing sync procedure is dropped.
+	c.synchronizer.cancelRanges()
+	c.synchronizer = newSynchronizer(c.db, c, c.eventBus)
+
+	l.WithField("tip", util.StringifyBytes(tip.Header.Hash)).
+		Info("rebuild chain completed")
+
+	return nil
 }
 
 //nolint
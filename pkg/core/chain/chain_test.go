@@ -9,6 +9,8 @@ package chain
 import (
 	"bytes"
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -126,6 +128,140 @@ func TestAcceptBlock(t *testing.T) {
 	assert.True(decodedBlk.Equals(c.tip))
 }
 
+// TestImportBlock ensures blocks at or below the chain tip are skipped only if
+// they match the accepted ones.
+func TestImportBlock(t *testing.T) {
+	assert := assert.New(t)
+	_, c := setupChainTest(t, 1)
+
+	blk := helper.RandomBlock(c.tip.Header.Height+1, 1)
+	assert.NoError(c.ImportBlock(*blk))
+	assert.True(bytes.Equal(blk.Header.Hash, c.tip.Header.Hash))
+
+	// The same block is already accepted
+	assert.Equal(ErrBlockAlreadyAccepted, c.ImportBlock(*blk))
+
+	// A different block at an accepted height conflicts with the chain
+	conflicting := helper.RandomBlock(blk.Header.Height, 1)
+	assert.Equal(errConflictingBlock, c.ImportBlock(*conflicting))
+	assert.True(bytes.Equal(blk.Header.Hash, c.tip.Header.Hash))
+}
+
+func TestResolveJournal(t *testing.T) {
+	assert := assert.New(t)
+	_, c := setupChainTest(t, 0)
+
+	fetchJournal := func() error {
+		return c.db.View(func(t database.Transaction) error {
+			_, err := t.FetchJournal()
+			return err
+		})
+	}
+
+	blockExists := func(b *block.Block) error {
+		return c.db.View(func(t database.Transaction) error {
+			_, err := t.FetchBlockExists(b.Header.Hash)
+			return err
+		})
+	}
+
+	// Block state transition fails on replay. Block must not be stored.
+	blk := mockAcceptableBlock(*c.tip)
+	blk.Header.StateHash = bytes.Repeat([]byte{1}, 32)
+
+	assert.NoError(c.db.Update(func(t database.Transaction) error {
+		return t.StoreJournal(blk)
+	}))
+
+	assert.NoError(c.syncWithRusk())
+	assert.Equal(database.ErrJournalEmpty, fetchJournal())
+	assert.Error(blockExists(blk))
+
+	// Block state transition succeeds on replay. Block must be stored.
+	blk = mockAcceptableBlock(*c.tip)
+
+	assert.NoError(c.db.Update(func(t database.Transaction) error {
+		return t.StoreJournal(blk)
+	}))
+
+	assert.NoError(c.syncWithRusk())
+	assert.Equal(database.ErrJournalEmpty, fetchJournal())
+	assert.NoError(blockExists(blk))
+
+	// Rusk is at the state of a journaled block not following the tip. It
+	// must be reverted before the block is replayed.
+	e := &journalExecutor{
+		PermissiveExecutor: transactions.MockExecutor(0),
+		root:               bytes.Repeat([]byte{2}, 32),
+	}
+	c.proxy = &transactions.MockProxy{E: e}
+
+	blk = mockAcceptableBlock(*c.tip)
+	blk.Header.Height = c.tip.Header.Height + 2
+	blk.Header.PrevBlockHash = bytes.Repeat([]byte{3}, 32)
+	blk.Header.StateHash = bytes.Repeat([]byte{2}, 32)
+
+	assert.NoError(c.db.Update(func(t database.Transaction) error {
+		return t.StoreJournal(blk)
+	}))
+
+	_, err := c.resolveJournal()
+	assert.NoError(err)
+	assert.True(e.reverted)
+	assert.Equal(database.ErrJournalEmpty, fetchJournal())
+	assert.Error(blockExists(blk))
+
+	// Rusk persisted the state of the journaled block, but the node crashed
+	// before the block was stored. The block must be stored without
+	// reverting Rusk.
+	e = &journalExecutor{
+		PermissiveExecutor: transactions.MockExecutor(0),
+		root:               bytes.Repeat([]byte{4}, 32),
+	}
+	c.proxy = &transactions.MockProxy{E: e}
+
+	blk = mockAcceptableBlock(*c.tip)
+	blk.Header.Height = c.tip.Header.Height + 1
+	blk.Header.StateHash = bytes.Repeat([]byte{4}, 32)
+
+	assert.NoError(c.db.Update(func(t database.Transaction) error {
+		return t.StoreJournal(blk)
+	}))
+
+	assert.NoError(c.syncWithRusk())
+	assert.False(e.reverted)
+	assert.Equal(blk.Header.StateHash, e.persisted)
+	assert.Equal(database.ErrJournalEmpty, fetchJournal())
+	assert.NoError(blockExists(blk))
+	assert.Equal(blk.Header.Hash, c.tip.Header.Hash)
+}
+
+// journalExecutor reports a Rusk state root, until it is reverted.
+type journalExecutor struct {
+	*transactions.PermissiveExecutor
+	root      []byte
+	persisted []byte
+	reverted  bool
+}
+
+func (e *journalExecutor) Persist(ctx context.Context, stateHash []byte) error {
+	e.persisted = stateHash
+	return nil
+}
+
+func (e *journalExecutor) GetStateRoot(ctx context.Context) ([]byte, error) {
+	if e.reverted {
+		return e.PermissiveExecutor.GetStateRoot(ctx)
+	}
+
+	return e.root, nil
+}
+
+func (e *journalExecutor) Revert(ctx context.Context) ([]byte, error) {
+	e.reverted = true
+	return e.PermissiveExecutor.GetStateRoot(ctx)
+}
+
 func createLoader(db database.DB) *DBLoader {
 	// genesis := helper.RandomBlock(0, 12)
 	return NewDBLoader(db, genesis.Decode())
@@ -170,6 +306,125 @@ func TestSyncProgress(t *testing.T) {
 	assert.Equal(resp.Progress, float32(50.0))
 }
 
+func TestRebuildChain(t *testing.T) {
+	assert := assert.New(t)
+	_, c := setupChainTest(t, 1)
+
+	genesisRoot := genesis.Decode().Header.StateHash
+
+	// Contract Storage is reset to the genesis state
+	c.proxy = &transactions.MockProxy{E: &revertExecutor{
+		PermissiveExecutor: transactions.MockExecutor(1),
+		root:               genesisRoot,
+	}}
+
+	// Start from a clean state, whatever the previous tests stored.
+	_, err := c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.NoError(err)
+
+	// Contract Storage is ahead of the genesis state, which is the most recent
+	// finalized one. It is reverted.
+	ahead := &revertExecutor{
+		PermissiveExecutor: transactions.MockExecutor(1),
+		root:               []byte{1},
+		finalized:          genesisRoot,
+	}
+	c.proxy = &transactions.MockProxy{E: ahead}
+
+	_, err = c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.NoError(err)
+	assert.True(ahead.reverted)
+
+	// Contract Storage cannot be reverted to the genesis state
+	c.proxy = &transactions.MockProxy{E: &revertExecutor{
+		PermissiveExecutor: transactions.MockExecutor(1),
+		root:               []byte{1},
+		finalized:          []byte{2},
+	}}
+
+	_, err = c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.Equal(errNotGenesisState, err)
+
+	// Consensus is not restarted after a failed reset
+	assert.Eventually(func() bool {
+		return !c.isConsensusRunning()
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(c.RestartConsensus())
+
+	blk := helper.RandomBlock(1, 1)
+	assert.NoError(c.acceptBlock(*blk, true))
+	assert.Equal(uint64(1), c.tip.Header.Height)
+
+	// A block other than genesis is finalized. Contract Storage is not
+	// reverted and the chain is left untouched.
+	finalized := &revertExecutor{
+		PermissiveExecutor: transactions.MockExecutor(0),
+		root:               []byte{1},
+		finalized:          genesisRoot,
+	}
+	c.proxy = &transactions.MockProxy{E: finalized}
+
+	// The precondition is checked before consensus is stopped
+	loopID := atomic.LoadUint64(&c.loopID)
+
+	_, err = c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.True(errors.Is(err, errNotGenesisState))
+	assert.False(finalized.reverted)
+	assert.True(bytes.Equal(blk.Header.Hash, c.tip.Header.Hash))
+	assert.Equal(loopID, atomic.LoadUint64(&c.loopID))
+
+	// Contract Storage is reset to the genesis state
+	c.proxy = &transactions.MockProxy{E: &revertExecutor{
+		PermissiveExecutor: transactions.MockExecutor(1),
+		root:               genesisRoot,
+	}}
+
+	// Forks and blacklisted blocks are reset along with the chain
+	fork := helper.RandomBlock(1, 1)
+	c.forks.add(*fork)
+	c.blacklisted.Add(bytes.NewBuffer(fork.Header.Hash))
+
+	// The running consensus loop is restarted once the chain is reset
+	assert.True(c.isConsensusRunning())
+
+	resp, err := c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.NoError(err)
+	assert.NotEmpty(resp.Response)
+	assert.Equal(loopID+1, atomic.LoadUint64(&c.loopID))
+	assert.False(c.forks.has(fork.Header.Hash))
+	assert.False(c.blacklisted.Has(bytes.NewBuffer(fork.Header.Hash)))
+
+	// Chain tip must point at genesis again
+	assert.Equal(uint64(0), c.tip.Header.Height)
+	assert.True(bytes.Equal(genesis.Decode().Header.Hash, c.tip.Header.Hash))
+
+	// Previously accepted block must be gone
+	err = c.db.View(func(t database.Transaction) error {
+		_, err := t.FetchBlockExists(blk.Header.Hash)
+		return err
+	})
+	assert.Equal(database.ErrBlockNotFound, err)
+}
+
+// revertExecutor mocks Contract Storage at a fixed state, which can be
+// reverted to a fixed finalized state.
+type revertExecutor struct {
+	*transactions.PermissiveExecutor
+	root      []byte
+	finalized []byte
+	reverted  bool
+}
+
+func (e *revertExecutor) GetStateRoot(context.Context) ([]byte, error) {
+	return e.root, nil
+}
+
+func (e *revertExecutor) Revert(context.Context) ([]byte, error) {
+	e.root = e.finalized
+	e.reverted = true
+	return e.root, nil
+}
+
 func TestFallbackProcedure(t *testing.T) {
 	t.Skip()
 
@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/config/genesis"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+)
+
+var (
+	errCheckpointMismatch = errors.New("block conflicts with checkpoint")
+	errRevertCheckpoint   = errors.New("revert past checkpoint")
+)
+
+// testnetCheckpoints are the known blocks of the test network.
+var testnetCheckpoints = map[uint64]string{
+	0: "804ca91cf306c7206e38910381d96a766268c2835d3213683dc7fafae42db23f",
+}
+
+// defaultCheckpoints maps a network, as set in the general configuration, to
+// the hex encoded hashes of its blocks known to be part of the network chain,
+// by height. The checkpoints of the configured network are loaded along with
+// the ones set in the general configuration.
+var defaultCheckpoints = map[string]map[uint64]string{
+	"test":    testnetCheckpoints,
+	"testnet": testnetCheckpoints,
+	"harness": {},
+	"mainnet": {},
+}
+
+// checkpoints maps a block height to the hash of the block known to be part
+// of the network chain at that height.
+type checkpoints map[uint64][]byte
+
+// loadCheckpoints returns the genesis block of the configured network along
+// with its default checkpoints and the checkpoints set in the general
+// configuration.
+func loadCheckpoints() (checkpoints, error) {
+	cp := checkpoints{0: genesis.Decode().Header.Hash}
+
+	for height, hash := range defaultCheckpoints[config.Get().General.Network] {
+		if err := cp.add(height, hash); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range config.Get().General.Checkpoints {
+		if err := cp.add(c.Height, c.Hash); err != nil {
+			return nil, err
+		}
+	}
+
+	return cp, nil
+}
+
+// add sets a checkpoint from a hex encoded block hash. It returns an error if
+// another checkpoint is set at the same height.
+func (cp checkpoints) add(height uint64, hexHash string) error {
+	hash, err := hex.DecodeString(hexHash)
+	if err != nil {
+		return fmt.Errorf("checkpoint at height %d: %w", height, err)
+	}
+
+	if len(hash) != block.HeaderHashSize {
+		return fmt.Errorf("checkpoint at height %d: invalid hash size %d", height, len(hash))
+	}
+
+	if h, ok := cp[height]; ok && !bytes.Equal(h, hash) {
+		return fmt.Errorf("conflicting checkpoints at height %d", height)
+	}
+
+	cp[height] = hash
+	return nil
+}
+
+// check returns an error if a checkpoint is set at the header height with a
+// different hash.
+func (cp checkpoints) check(header *block.Header) error {
+	hash, ok := cp[header.Height]
+	if !ok || bytes.Equal(hash, header.Hash) {
+		return nil
+	}
+
+	return fmt.Errorf("%w at height %d: expected %s, got %s", errCheckpointMismatch,
+		header.Height, hex.EncodeToString(hash), hex.EncodeToString(header.Hash))
+}
+
+// checkRevert returns an error if reverting the chain from the tip height down
+// to the given height would delete a checkpoint block.
+func (cp checkpoints) checkRevert(to, tip uint64) error {
+	for height := range cp {
+		if height > to && height <= tip {
+			return fmt.Errorf("%w at height %d: cannot revert to height %d", errRevertCheckpoint, height, to)
+		}
+	}
+
+	return nil
+}
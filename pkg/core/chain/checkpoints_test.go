@@ -0,0 +1,100 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/config/genesis"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestLoadCheckpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	cp, err := loadCheckpoints()
+	assert.NoError(err)
+
+	// The genesis block is always a checkpoint
+	assert.NoError(cp.check(genesis.Decode().Header))
+	assert.True(errors.Is(cp.check(helper.RandomBlock(0, 1).Header), errCheckpointMismatch))
+}
+
+// TestDefaultCheckpoints ensures the default checkpoints of each network
+// match its genesis block.
+func TestDefaultCheckpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(r config.Registry) {
+		config.Mock(&r)
+	}(config.Get())
+
+	for network := range defaultCheckpoints {
+		if _, err := genesis.GetPresetConfig(network); err != nil {
+			continue
+		}
+
+		r := config.Get()
+		r.General.Network = network
+		config.Mock(&r)
+
+		_, err := loadCheckpoints()
+		assert.NoError(err, network)
+	}
+}
+
+func TestLoadDefaultCheckpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(d map[string]map[uint64]string) {
+		defaultCheckpoints = d
+	}(defaultCheckpoints)
+
+	blk := helper.RandomBlock(100, 1)
+	other := helper.RandomBlock(200, 1)
+
+	defaultCheckpoints = map[string]map[uint64]string{
+		config.Get().General.Network: {100: hex.EncodeToString(blk.Header.Hash)},
+		"othernet":                   {200: hex.EncodeToString(other.Header.Hash)},
+	}
+
+	cp, err := loadCheckpoints()
+	assert.NoError(err)
+
+	// Only the checkpoints of the configured network are loaded
+	assert.NoError(cp.check(blk.Header))
+	assert.True(errors.Is(cp.check(helper.RandomBlock(100, 1).Header), errCheckpointMismatch))
+	assert.NoError(cp.check(helper.RandomBlock(200, 1).Header))
+
+	// A default checkpoint conflicting with the genesis block is rejected
+	defaultCheckpoints[config.Get().General.Network][0] = hex.EncodeToString(blk.Header.Hash)
+
+	_, err = loadCheckpoints()
+	assert.Error(err)
+}
+
+func TestCheckpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	blk := helper.RandomBlock(100, 1)
+	cp := checkpoints{100: blk.Header.Hash}
+
+	assert.NoError(cp.check(blk.Header))
+
+	// Blocks at other heights are not constrained
+	assert.NoError(cp.check(helper.RandomBlock(99, 1).Header))
+	assert.True(errors.Is(cp.check(helper.RandomBlock(100, 1).Header), errCheckpointMismatch))
+
+	assert.NoError(cp.checkRevert(100, 120))
+	assert.NoError(cp.checkRevert(50, 99))
+	assert.True(errors.Is(cp.checkRevert(99, 120), errRevertCheckpoint))
+	assert.True(errors.Is(cp.checkRevert(50, 100), errRevertCheckpoint))
+}
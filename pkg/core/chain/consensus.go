@@ -23,6 +23,11 @@ func (c *Chain) RestartConsensus() error {
 	return c.startConsensus()
 }
 
+// isConsensusRunning returns true if a consensus loop is running.
+func (c *Chain) isConsensusRunning() bool {
+	return atomic.LoadUint64(&c.runningLoopID) != 0
+}
+
 // startConsensus will start the consensus loop. It can be halted at any point by
 // sending a signal through the `stopConsensus` channel (`StopConsensus`
 // as exposed by the `Ledger` interface).
@@ -40,9 +45,12 @@ func (c *Chain) startConsensus() error {
 		return err
 	}
 
+	atomic.StoreUint64(&c.runningLoopID, id)
+
 	go func(ctx context.Context, cancel context.CancelFunc, winnerChan chan consensus.Results) {
 		defer cancel()
 		defer log.WithField("id", id).Info("consensus_loop terminated")
+		defer atomic.CompareAndSwapUint64(&c.runningLoopID, id, 0)
 
 		c.acceptConsensusResults(ctx, winnerChan)
 	}(ctx, cancel, winnerChan)
@@ -0,0 +1,230 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+)
+
+// DBCheckIssue describes a single inconsistency found in the chain database.
+type DBCheckIssue struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash,omitempty"`
+	Check  string `json:"check"`
+	Error  string `json:"error"`
+}
+
+// DBCheckRange is an inclusive range of block heights.
+type DBCheckRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// DBCheckReport is the result of a database consistency check.
+type DBCheckReport struct {
+	TipHeight     uint64         `json:"tip_height"`
+	CheckedBlocks uint64         `json:"checked_blocks"`
+	Issues        []DBCheckIssue `json:"issues"`
+	// UncheckedCertificates lists the heights of the blocks whose certificate
+	// was not verified.
+	UncheckedCertificates []DBCheckRange `json:"unchecked_certificates"`
+}
+
+func (r *DBCheckReport) add(height uint64, hash []byte, check string, err error) {
+	issue := DBCheckIssue{
+		Height: height,
+		Check:  check,
+		Error:  err.Error(),
+	}
+
+	if len(hash) > 0 {
+		issue.Hash = hex.EncodeToString(hash)
+	}
+
+	r.Issues = append(r.Issues, issue)
+}
+
+func (r *DBCheckReport) addUnchecked(height uint64) {
+	if n := len(r.UncheckedCertificates); n > 0 && r.UncheckedCertificates[n-1].To+1 == height {
+		r.UncheckedCertificates[n-1].To = height
+		return
+	}
+
+	r.UncheckedCertificates = append(r.UncheckedCertificates, DBCheckRange{From: height, To: height})
+}
+
+// CheckDatabase verifies the consistency of the entire chain database. Unlike
+// DBLoader.SanityCheckBlockchain, it does not stop at the first inconsistency
+// but reports all of them.
+//
+// Block certificates are verified only if provisioners is not nil. The
+// provisioners set must be the one in force at the chain tip. As it is updated
+// with the first block of each epoch, only the certificates of the blocks of
+// the tip epoch are verified. The heights of the other blocks, except for the
+// genesis one, are reported in UncheckedCertificates.
+func CheckDatabase(db database.DB, provisioners *user.Provisioners) (*DBCheckReport, error) {
+	report := &DBCheckReport{
+		Issues:                make([]DBCheckIssue, 0),
+		UncheckedCertificates: make([]DBCheckRange, 0),
+	}
+
+	err := db.View(func(t database.Transaction) error {
+		tipHeight := checkRegistry(t, report)
+		report.TipHeight = tipHeight
+
+		var prevHeader *block.Header
+
+		// Blocks are checked up to the chain tip only. If the tip is unknown,
+		// the issue is already reported and the genesis block alone is checked.
+		for height := uint64(0); height <= tipHeight; height++ {
+			hash, err := t.FetchBlockHashByHeight(height)
+			if err != nil {
+				report.add(height, nil, "height_index", err)
+				prevHeader = nil
+				continue
+			}
+
+			p := provisioners
+			if height == 0 || (height-1)/config.EPOCH != tipHeight/config.EPOCH {
+				p = nil
+			}
+
+			if p == nil && height > 0 {
+				report.addUnchecked(height)
+			}
+
+			prevHeader = checkBlock(t, report, height, hash, prevHeader, p)
+			report.CheckedBlocks++
+		}
+
+		// There shouldn't be any blocks higher than the chain tip
+		if hash, err := t.FetchBlockHashByHeight(tipHeight + 1); err == nil && len(hash) > 0 {
+			report.add(tipHeight+1, hash, "height_index", errors.New("block stored above the chain tip"))
+		}
+
+		return checkTxIndex(t, report)
+	})
+
+	return report, err
+}
+
+// checkRegistry verifies that both tip and persisted blocks exist. It returns
+// the tip height, as FetchCurrentHeight does, if known.
+func checkRegistry(t database.Transaction, report *DBCheckReport) uint64 {
+	r, err := t.FetchRegistry()
+	if err != nil {
+		report.add(0, nil, "registry", err)
+		return 0
+	}
+
+	if _, err = t.FetchBlockExists(r.PersistedHash); err != nil {
+		report.add(0, r.PersistedHash, "registry_persisted", err)
+	}
+
+	header, err := t.FetchBlockHeader(r.TipHash)
+	if err != nil {
+		report.add(0, r.TipHash, "registry_tip", err)
+		return 0
+	}
+
+	return header.Height
+}
+
+// checkBlock verifies all records of a single block. It returns the block
+// header, if found.
+func checkBlock(t database.Transaction, report *DBCheckReport, height uint64, hash []byte, prevHeader *block.Header, provisioners *user.Provisioners) *block.Header {
+	header, err := t.FetchBlockHeader(hash)
+	if err != nil {
+		report.add(height, hash, "header", err)
+		return nil
+	}
+
+	if header.Height != height {
+		report.add(height, hash, "header_height", errors.New("height index points at a block from another height"))
+	}
+
+	if h, err := header.CalculateHash(); err != nil || !bytes.Equal(h, hash) {
+		if err == nil {
+			err = errors.New("header hash mismatch")
+		}
+
+		report.add(height, hash, "header_hash", err)
+	}
+
+	if prevHeader != nil && !bytes.Equal(header.PrevBlockHash, prevHeader.Hash) {
+		report.add(height, hash, "prev_hash", errors.New("invalid link to previous block"))
+	}
+
+	txs, err := t.FetchBlockTxs(hash)
+
+	switch {
+	case err == database.ErrBlockPruned:
+		// No transactions to check
+	case err != nil:
+		report.add(height, hash, "txs", err)
+	default:
+		checkBlockTxs(t, report, height, hash, txs)
+	}
+
+	if provisioners != nil && prevHeader != nil {
+		blk := block.Block{Header: header}
+		if err := checkBlockCertificate(*provisioners, blk, prevHeader.Seed); err != nil {
+			report.add(height, hash, "certificate", err)
+		}
+	}
+
+	return header
+}
+
+// checkTxIndex verifies that each record of the TxID index points to a stored
+// block holding the transaction.
+func checkTxIndex(t database.Transaction, report *DBCheckReport) error {
+	return t.IterateTxIDs(func(txID, blockHash []byte) (bool, error) {
+		header, err := t.FetchBlockHeader(blockHash)
+		if err != nil {
+			report.add(0, blockHash, "txid_index", fmt.Errorf("tx %s points to a missing block: %v", hex.EncodeToString(txID), err))
+			return true, nil
+		}
+
+		_, _, _, err = t.FetchBlockTxByHash(txID)
+		if err != nil && err != database.ErrBlockPruned {
+			report.add(header.Height, blockHash, "txid_index", fmt.Errorf("tx %s points to a missing tx: %v", hex.EncodeToString(txID), err))
+		}
+
+		return true, nil
+	})
+}
+
+// checkBlockTxs verifies that each block transaction is reachable by its TxID.
+func checkBlockTxs(t database.Transaction, report *DBCheckReport, height uint64, hash []byte, txs []transactions.ContractCall) {
+	for i, tx := range txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			report.add(height, hash, "tx_id", err)
+			continue
+		}
+
+		_, txIndex, blockHash, err := t.FetchBlockTxByHash(txID)
+		if err != nil {
+			report.add(height, hash, "tx_index", err)
+			continue
+		}
+
+		if !bytes.Equal(blockHash, hash) || txIndex != uint32(i) {
+			report.add(height, hash, "tx_index", fmt.Errorf("tx %s is indexed at another block or position", hex.EncodeToString(txID)))
+		}
+	}
+}
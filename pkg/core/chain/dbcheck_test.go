@@ -0,0 +1,178 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCheckDatabase(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	// Store a chain of linked blocks
+	blocks := linkedBlocks(t, 5)
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, b := range blocks {
+			if err := t.StoreBlock(b, true); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	report, err := CheckDatabase(db, nil)
+	assert.NoError(err)
+	assert.Empty(report.Issues)
+	assert.Equal(uint64(5), report.CheckedBlocks)
+	assert.Equal(uint64(4), report.TipHeight)
+
+	// Store a block that does not link to the chain tip
+	b := helper.RandomBlock(5, 1)
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		return t.StoreBlock(b, false)
+	}))
+
+	report, err = CheckDatabase(db, nil)
+	assert.NoError(err)
+	assert.Len(report.Issues, 1)
+	assert.Equal("prev_hash", report.Issues[0].Check)
+	assert.Equal(uint64(5), report.Issues[0].Height)
+
+	// Store a block above the chain tip. The tip is set back to block 5.
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		if err := t.StoreBlock(helper.RandomBlock(6, 1), false); err != nil {
+			return err
+		}
+
+		return t.StoreBlock(b, false)
+	}))
+
+	report, err = CheckDatabase(db, nil)
+	assert.NoError(err)
+	assert.Equal(uint64(6), report.CheckedBlocks)
+	assert.Len(report.Issues, 2)
+	assert.Equal("height_index", report.Issues[1].Check)
+	assert.Equal(uint64(6), report.Issues[1].Height)
+}
+
+func TestCheckDatabaseTxIndex(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	blocks := linkedBlocks(t, 5)
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, b := range blocks[:4] {
+			if err := t.StoreBlock(b, true); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	// Store a block and then delete all of its records but its txs ones, so
+	// that its txs are indexed at a missing block
+	tip := blocks[4]
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		if err := t.StoreBlock(tip, false); err != nil {
+			return err
+		}
+
+		return t.DeleteBlock(&block.Block{Header: tip.Header})
+	}))
+
+	report, err := CheckDatabase(db, nil)
+	assert.NoError(err)
+
+	var dangling int
+
+	for _, issue := range report.Issues {
+		if issue.Check == "txid_index" {
+			assert.Equal(tip.Header.Hash, mustDecodeHex(t, issue.Hash))
+			dangling++
+		}
+	}
+
+	assert.Equal(len(tip.Txs), dangling)
+}
+
+func TestCheckDatabaseCertificates(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	checked := make([]uint64, 0)
+	checkBlockCertificate = func(_ user.Provisioners, b block.Block, _ []byte) error {
+		checked = append(checked, b.Header.Height)
+		return nil
+	}
+
+	blocks := linkedBlocks(t, 5)
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, b := range blocks {
+			if err := t.StoreBlock(b, true); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	// Certificates are not verified without provisioners
+	report, err := CheckDatabase(db, nil)
+	assert.NoError(err)
+	assert.Empty(checked)
+	assert.Equal([]DBCheckRange{{From: 1, To: 4}}, report.UncheckedCertificates)
+
+	// All blocks following the genesis are in the tip epoch
+	report, err = CheckDatabase(db, user.NewProvisioners())
+	assert.NoError(err)
+	assert.Empty(report.Issues)
+	assert.Empty(report.UncheckedCertificates)
+	assert.Equal([]uint64{1, 2, 3, 4}, checked)
+}
+
+func linkedBlocks(t *testing.T, count int) []*block.Block {
+	blocks := make([]*block.Block, count)
+
+	for i := 0; i < count; i++ {
+		b := helper.RandomBlock(uint64(i), 1)
+
+		if i > 0 {
+			b.Header.PrevBlockHash = blocks[i-1].Header.Hash
+
+			hash, err := b.CalculateHash()
+			assert.NoError(t, err)
+
+			b.Header.Hash = hash
+		}
+
+		blocks[i] = b
+	}
+
+	return blocks
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+
+	return b
+}
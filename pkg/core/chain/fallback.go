@@ -43,7 +43,14 @@ func (c *Chain) allowFallback(b block.Block, l *logrus.Entry) error {
 		return errors.New("more the one winning block for the same iteration")
 	}
 
-	return nil
+	// Contract Storage is reverted to the most recent finalized block, which
+	// must not precede a checkpoint.
+	finalized, _, err := c.fetchFinalizedSince(&prevBlk)
+	if err != nil {
+		return err
+	}
+
+	return c.checkpoints.checkRevert(finalized.Header.Height, c.tip.Header.Height)
 }
 
 func (c *Chain) tryFallback(b block.Block) error {
@@ -97,21 +104,31 @@ func (c *Chain) tryFallback(b block.Block) error {
 	}
 
 	// revert blockchain from current tip to finalized block
-	err = c.revertBlockchain(c.tip, finalized, llog)
+	reverted, err := c.revertBlockchain(c.tip, finalized, llog)
 	if err != nil {
 		return err
 	}
 
+	// resubmit txs back to mempool
+	for _, b := range reverted {
+		go c.resubmitTxs(b.Txs)
+	}
+
 	llog.Info("completed")
 
 	return nil
 }
 
-func (c *Chain) revertBlockchain(from, to *block.Block, llog *logrus.Entry) error {
+// revertBlockchain deletes all blocks from the block from down to the block
+// to (included), and stores the block to as the new persisted tip. It returns
+// the deleted blocks in descending height order.
+func (c *Chain) revertBlockchain(from, to *block.Block, llog *logrus.Entry) ([]block.Block, error) {
 	llog.WithField("from", from.Header.Height).
 		WithField("to", to.Header.Height).
 		Info("revert blockchain")
 
+	reverted := make([]block.Block, 0)
+
 	err := c.db.Update(func(t database.Transaction) error {
 		// Delete all non-finalized blocks
 		for h := from.Header.Height; h >= to.Header.Height; h-- {
@@ -135,12 +152,15 @@ func (c *Chain) revertBlockchain(from, to *block.Block, llog *logrus.Entry) erro
 				Txs:    txs,
 			}
 
-			// resubmit txs back to mempool
-			go c.resubmitTxs(txs)
-
 			if err := t.DeleteBlock(&b); err != nil {
 				return err
 			}
+
+			reverted = append(reverted, b)
+
+			if h == 0 {
+				break
+			}
 		}
 
 		// Store new blockchain tip and persist
@@ -153,7 +173,7 @@ func (c *Chain) revertBlockchain(from, to *block.Block, llog *logrus.Entry) erro
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Restore provisioners set
@@ -165,7 +185,7 @@ func (c *Chain) revertBlockchain(from, to *block.Block, llog *logrus.Entry) erro
 
 	c.p = &provisioners
 
-	return nil
+	return reverted, nil
 }
 
 func (c *Chain) resubmitTxs(txs []transactions.ContractCall) {
@@ -177,64 +197,3 @@ func (c *Chain) resubmitTxs(txs []transactions.ContractCall) {
 		}
 	}
 }
-
-// isBlockFromFork returns true if a valid block from a fork is detected by
-// satisfying the following conditions.
-//
-// - block is from the past, in other words its height is lower than local tip height
-// - block hash does not exist in the local blockchain state
-// - block is valid to its predecessor fetched from local blockchain state.
-func (c *Chain) isBlockFromFork(b block.Block) (bool, error) {
-	var (
-		th = c.tip.Header.Height
-		rh = b.Header.Height
-		pb *block.Block
-	)
-
-	if rh >= th {
-		// block is not from the past
-		return false, nil
-	}
-
-	// Sanity-check if this block has been already accepted
-	var exists bool
-
-	_ = c.db.View(func(t database.Transaction) error {
-		exists, _ = t.FetchBlockExists(b.Header.Hash)
-		return nil
-	})
-
-	if exists {
-		// block already exists in local blockchain state
-		return false, nil
-	}
-
-	// Fetch the predecessor block of the received one
-	err := c.db.View(func(t database.Transaction) error {
-		var e error
-		h, e := t.FetchBlockHashByHeight(b.Header.Height - 1)
-		if e != nil {
-			return e
-		}
-
-		pb, e = t.FetchBlock(h)
-		if e != nil {
-			return e
-		}
-
-		return nil
-	})
-	if err != nil {
-		return false, err
-	}
-
-	// A weak assumption is made here that provisioners state has not changed
-	// since recvBlk.Header.Height
-
-	err = c.isValidHeader(b, *pb, *c.p, log, true)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
-}
@@ -0,0 +1,458 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxForkBlocks is the max number of blocks from competing branches
+	// tracked at a time.
+	maxForkBlocks = 500
+	// maxForkDepth is the max number of local blocks a competing branch may
+	// replace.
+	maxForkDepth = 100
+)
+
+var (
+	errForkBelowFinalized = errors.New("fork below the most recent finalized block")
+	errForkTooDeep        = errors.New("fork ancestor too far below the chain tip")
+	errForkPastEpoch      = errors.New("fork block following a block of a past epoch")
+	errUnknownGenerator   = errors.New("block generator is not a provisioner")
+)
+
+// forkTracker keeps blocks from competing branches, as received from peers,
+// until they are either linked to the local blockchain or evicted.
+type forkTracker struct {
+	blocks map[string]block.Block
+	// byHeight lists the hashes of tracked blocks in ascending height order.
+	byHeight []string
+}
+
+func newForkTracker() *forkTracker {
+	return &forkTracker{
+		blocks:   make(map[string]block.Block),
+		byHeight: make([]string, 0, maxForkBlocks),
+	}
+}
+
+func (f *forkTracker) has(hash []byte) bool {
+	_, ok := f.blocks[string(hash)]
+	return ok
+}
+
+// search returns the index of the first block in byHeight at or above the
+// height.
+func (f *forkTracker) search(height uint64) int {
+	return sort.Search(len(f.byHeight), func(i int) bool {
+		return f.blocks[f.byHeight[i]].Header.Height >= height
+	})
+}
+
+// add tracks a block. If the tracker is full, the block with the lowest
+// height is evicted.
+func (f *forkTracker) add(b block.Block) {
+	k := string(b.Header.Hash)
+	if _, ok := f.blocks[k]; ok {
+		return
+	}
+
+	if len(f.byHeight) >= maxForkBlocks {
+		delete(f.blocks, f.byHeight[0])
+		f.byHeight = f.byHeight[1:]
+	}
+
+	i := f.search(b.Header.Height)
+	f.byHeight = append(f.byHeight, "")
+	copy(f.byHeight[i+1:], f.byHeight[i:])
+	f.byHeight[i] = k
+
+	f.blocks[k] = b
+}
+
+func (f *forkTracker) remove(hash []byte) {
+	k := string(hash)
+
+	b, ok := f.blocks[k]
+	if !ok {
+		return
+	}
+
+	for i := f.search(b.Header.Height); i < len(f.byHeight); i++ {
+		if f.byHeight[i] == k {
+			f.byHeight = append(f.byHeight[:i], f.byHeight[i+1:]...)
+			break
+		}
+	}
+
+	delete(f.blocks, k)
+}
+
+// branch returns the tracked blocks linked to b, ending at b, in ascending
+// height order.
+func (f *forkTracker) branch(b block.Block) []block.Block {
+	branch := []block.Block{b}
+
+	for {
+		prev, ok := f.blocks[string(branch[0].Header.PrevBlockHash)]
+		if !ok {
+			return branch
+		}
+
+		branch = append([]block.Block{prev}, branch...)
+	}
+}
+
+// isBetterBranch compares a competing branch with the local one, both
+// starting right after their common ancestor. Certificate steps are compared
+// block by block, the lowest consensus iteration being preferred. If all steps
+// are the same, the longest branch is preferred.
+func isBetterBranch(branch []block.Block, local []*block.Header) bool {
+	for i := 0; i < len(branch) && i < len(local); i++ {
+		bs := branch[i].Header.Certificate.Step
+		ls := local[i].Certificate.Step
+
+		if bs != ls {
+			return bs < ls
+		}
+	}
+
+	return len(branch) > len(local)
+}
+
+// processForkBlock tracks a block from a competing branch. If the branch is
+// linked to the local blockchain and it is better than the local one, the
+// chain is reorganized to the branch. It must be called with the chain lock
+// held.
+func (c *Chain) processForkBlock(blk block.Block, l *logrus.Entry) error {
+	var exists bool
+
+	_ = c.db.View(func(t database.Transaction) error {
+		exists, _ = t.FetchBlockExists(blk.Header.Hash)
+		return nil
+	})
+
+	if exists {
+		// block already exists in local blockchain state
+		return nil
+	}
+
+	// Only blocks following a known block are tracked, once verified. This
+	// prevents peers from filling the tracker with forged blocks.
+	parent, err := c.fetchForkParent(blk)
+	if err == database.ErrBlockNotFound {
+		l.Debug("fork block parent unknown")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = c.verifyForkBlock(blk, *parent); err != nil {
+		return err
+	}
+
+	c.forks.add(blk)
+	branch := c.forks.branch(blk)
+
+	// Find the common ancestor and the local blocks after it
+	var (
+		ancestor *block.Block
+		local    []*block.Header
+	)
+
+	err = c.db.View(func(t database.Transaction) error {
+		var e error
+
+		ancestor, e = t.FetchBlock(branch[0].Header.PrevBlockHash)
+		if e != nil {
+			return e
+		}
+
+		if c.tip.Header.Height-ancestor.Header.Height > maxForkDepth {
+			return errForkTooDeep
+		}
+
+		return t.IterateHeaders(ancestor.Header.Height+1, c.tip.Header.Height, func(header *block.Header) (bool, error) {
+			local = append(local, header)
+			return true, nil
+		})
+	})
+
+	if err == database.ErrBlockNotFound {
+		l.Debug("branch not linked to local blockchain yet")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !isBetterBranch(branch, local) {
+		return nil
+	}
+
+	l = l.WithField("event", "reorg").
+		WithField("ancestor_h", ancestor.Header.Height).
+		WithField("branch_len", len(branch)).
+		WithField("recv_blk_hash", hex.EncodeToString(blk.Header.Hash))
+
+	l.Info("better branch detected")
+
+	// Ensure the whole branch is valid, block by block, starting from the
+	// common ancestor, before any destructive step. As the ancestor is in the
+	// current epoch, see verifyForkBlock, a weak assumption is made here that
+	// provisioners state has not changed since the ancestor.
+	prev := *ancestor
+
+	for _, b := range branch {
+		if err = c.isValidHeader(b, prev, *c.p, l, true); err != nil {
+			for _, b := range branch {
+				c.forks.remove(b.Header.Hash)
+			}
+
+			return err
+		}
+
+		prev = b
+	}
+
+	return c.reorg(ancestor, branch, l)
+}
+
+// fetchForkParent returns the parent of a fork block, either tracked or
+// stored in the local blockchain.
+func (c *Chain) fetchForkParent(blk block.Block) (*block.Block, error) {
+	if prev, ok := c.forks.blocks[string(blk.Header.PrevBlockHash)]; ok {
+		return &prev, nil
+	}
+
+	var prev *block.Block
+
+	err := c.db.View(func(t database.Transaction) error {
+		var err error
+		prev, err = t.FetchBlock(blk.Header.PrevBlockHash)
+		return err
+	})
+
+	return prev, err
+}
+
+// verifyForkBlock checks a fork block against its parent. The block must be
+// generated by a provisioner and its certificate must be valid.
+//
+// The provisioners set is updated once the first block of an epoch is
+// accepted. Blocks following a block of a past epoch can not be checked
+// against the current set, and are refused.
+func (c *Chain) verifyForkBlock(blk, prev block.Block) error {
+	if prev.Header.Height/config.EPOCH < c.tip.Header.Height/config.EPOCH {
+		return errForkPastEpoch
+	}
+
+	if err := verifiers.CheckBlockHeader(prev, blk); err != nil {
+		return invalidBlockError{err}
+	}
+
+	if c.p.GetMember(blk.Header.GeneratorBlsPubkey) == nil {
+		return invalidBlockError{errUnknownGenerator}
+	}
+
+	if err := checkBlockCertificate(*c.p, blk, prev.Header.Seed); err != nil {
+		return invalidBlockError{err}
+	}
+
+	return nil
+}
+
+// reorg reverts the chain to the common ancestor and accepts the branch.
+//
+// Contract Storage can be reverted only to the most recent finalized state.
+// That's why the chain is reverted to the most recent finalized block first,
+// and blocks between the finalized block and the ancestor are re-accepted.
+func (c *Chain) reorg(ancestor *block.Block, branch []block.Block, l *logrus.Entry) error {
+	finalized, shared, err := c.fetchFinalizedSince(ancestor)
+	if err != nil {
+		return err
+	}
+
+	if err = c.checkpoints.checkRevert(finalized.Header.Height, c.tip.Header.Height); err != nil {
+		return err
+	}
+
+	reverted, err := c.revertToFinalized(finalized, l)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range append(shared, branch...) {
+		if err = c.acceptBlock(b, true); err != nil {
+			l.WithError(err).Error("re-accepting branch failed")
+
+			if rerr := c.restoreBlocks(finalized, reverted, l); rerr != nil {
+				l.WithError(rerr).Error("restoring local blockchain failed")
+			}
+
+			return err
+		}
+	}
+
+	// Blocks from the local branch should be filtered out if propagated
+	// back by any other node.
+	orphaned := make([]block.Block, 0)
+
+	for _, b := range reverted {
+		if b.Header.Height > ancestor.Header.Height {
+			orphaned = append(orphaned, b)
+			c.blacklisted.Add(bytes.NewBuffer(b.Header.Hash))
+		}
+	}
+
+	for _, b := range branch {
+		c.forks.remove(b.Header.Hash)
+	}
+
+	go c.resubmitTxs(orphanedTxs(orphaned, branch))
+
+	if err = c.RestartConsensus(); err != nil {
+		l.WithError(err).Warn("reorg could not restart consensus loop")
+	}
+
+	l.WithField("curr_h", c.tip.Header.Height).Info("completed")
+	return nil
+}
+
+// revertToFinalized reverts both Contract Storage and the blockchain to the
+// finalized block. It returns the reverted blocks, in descending height order.
+func (c *Chain) revertToFinalized(finalized *block.Block, l *logrus.Entry) ([]block.Block, error) {
+	l.Info("revert contract storage")
+
+	stateHash, err := c.proxy.Executor().Revert(c.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l.WithField("finalized_state_hash", hex.EncodeToString(stateHash)).
+		Info("revert contract storage completed")
+
+	if !bytes.Equal(stateHash, finalized.Header.StateHash) {
+		l.WithField("node", hex.EncodeToString(finalized.Header.StateHash)).
+			WithError(errInvalidStateHash).
+			Error("reverted state does not match the finalized block")
+		return nil, errInvalidStateHash
+	}
+
+	if err = c.proxy.Executor().Persist(c.ctx, stateHash); err != nil {
+		return nil, err
+	}
+
+	return c.revertBlockchain(c.tip, finalized, l)
+}
+
+// restoreBlocks brings the chain back to the blocks reverted by a failed
+// reorg.
+func (c *Chain) restoreBlocks(finalized *block.Block, reverted []block.Block, l *logrus.Entry) error {
+	l.Warn("restore local blockchain")
+
+	if _, err := c.revertToFinalized(finalized, l); err != nil {
+		return err
+	}
+
+	for i := len(reverted) - 1; i >= 0; i-- {
+		if reverted[i].Header.Height <= finalized.Header.Height {
+			continue
+		}
+
+		if err := c.acceptBlock(reverted[i], false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchFinalizedSince returns the most recent finalized block at or below the
+// ancestor, along with the blocks following it up to the ancestor (included).
+// It fails if a block after the ancestor is finalized.
+func (c *Chain) fetchFinalizedSince(ancestor *block.Block) (*block.Block, []block.Block, error) {
+	var (
+		finalized *block.Block
+		shared    []block.Block
+	)
+
+	err := c.db.View(func(t database.Transaction) error {
+		for h := c.tip.Header.Height; ; h-- {
+			hash, err := t.FetchBlockHashByHeight(h)
+			if err != nil {
+				return err
+			}
+
+			b, err := t.FetchBlock(hash)
+			if err != nil {
+				return err
+			}
+
+			// Finalize is run on blocks of the first consensus iteration
+			if h == 0 || b.Header.Certificate.Step == 3 {
+				if h > ancestor.Header.Height {
+					return errForkBelowFinalized
+				}
+
+				finalized = b
+				return nil
+			}
+
+			if h <= ancestor.Header.Height {
+				shared = append([]block.Block{*b}, shared...)
+			}
+		}
+	})
+
+	return finalized, shared, err
+}
+
+// orphanedTxs returns the transactions of the orphaned blocks that are not
+// included in the branch.
+func orphanedTxs(orphaned, branch []block.Block) []transactions.ContractCall {
+	included := make(map[string]struct{})
+
+	for _, b := range branch {
+		for _, tx := range b.Txs {
+			if h, err := tx.CalculateHash(); err == nil {
+				included[string(h)] = struct{}{}
+			}
+		}
+	}
+
+	txs := make([]transactions.ContractCall, 0)
+
+	for _, b := range orphaned {
+		for _, tx := range b.Txs {
+			h, err := tx.CalculateHash()
+			if err != nil {
+				continue
+			}
+
+			if _, ok := included[string(h)]; !ok {
+				txs = append(txs, tx)
+			}
+		}
+	}
+
+	return txs
+}
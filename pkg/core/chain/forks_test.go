@@ -0,0 +1,332 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/key"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestIsBetterBranch(t *testing.T) {
+	branch := func(steps ...uint8) []block.Block {
+		blocks := make([]block.Block, len(steps))
+		for i, step := range steps {
+			blocks[i] = *forkBlock(t, helper.RandomBlock(0, 1), step)
+		}
+
+		return blocks
+	}
+
+	local := func(steps ...uint8) []*block.Header {
+		headers := make([]*block.Header, len(steps))
+		for i, b := range branch(steps...) {
+			headers[i] = b.Header
+		}
+
+		return headers
+	}
+
+	assert.True(t, isBetterBranch(branch(3), local(6, 6)))
+	assert.True(t, isBetterBranch(branch(6, 3), local(6, 6, 6)))
+	assert.True(t, isBetterBranch(branch(6, 6, 6), local(6, 6)))
+	assert.False(t, isBetterBranch(branch(9), local(6, 6)))
+	assert.False(t, isBetterBranch(branch(6, 6), local(6, 6)))
+	assert.False(t, isBetterBranch(branch(6), local(6, 3)))
+}
+
+func TestReorg(t *testing.T) {
+	assert := assert.New(t)
+
+	// Certificates of mocked blocks are not valid
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	checkBlockCertificate = func(user.Provisioners, block.Block, []byte) error {
+		return nil
+	}
+
+	_, c := setupForkTest(t)
+	var err error
+
+	// Local chain of blocks from the second consensus iteration
+	local := acceptForkBlocks(t, c, 3)
+
+	// A worse branch is only tracked
+	worse := forkBlock(t, local[0], 9)
+
+	_, err = c.ProcessBlockFromNetwork("", message.New(topics.Block, *worse))
+	assert.NoError(err)
+	assert.True(bytes.Equal(local[2].Header.Hash, c.tip.Header.Hash))
+	assert.True(c.forks.has(worse.Header.Hash))
+
+	// A better branch replaces the local blocks after the common ancestor
+	better := forkBlock(t, local[0], 3)
+
+	_, err = c.ProcessBlockFromNetwork("", message.New(topics.Block, *better))
+	assert.NoError(err)
+	assert.True(bytes.Equal(better.Header.Hash, c.tip.Header.Hash))
+	assert.False(c.forks.has(better.Header.Hash))
+
+	assert.True(blockExists(c, local[0]))
+	assert.True(blockExists(c, better))
+	assert.False(blockExists(c, local[1]))
+	assert.False(blockExists(c, local[2]))
+
+	// Orphaned blocks are filtered out
+	assert.True(c.blacklisted.Has(bytes.NewBuffer(local[2].Header.Hash)))
+}
+
+func TestReorgInvalidBranch(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	var invalid []byte
+
+	checkBlockCertificate = func(_ user.Provisioners, b block.Block, _ []byte) error {
+		if bytes.Equal(b.Header.Hash, invalid) {
+			return errors.New("invalid certificate")
+		}
+
+		return nil
+	}
+
+	eb, c := setupForkTest(t)
+	var err error
+
+	local := acceptForkBlocks(t, c, 3)
+
+	// The second block of the branch is invalid
+	branch := make([]*block.Block, 2)
+	prev := local[0]
+
+	for i := range branch {
+		branch[i] = forkBlock(t, prev, 6)
+		prev = branch[i]
+	}
+
+	invalid = branch[1].Header.Hash
+
+	misbehaviors := make(chan message.Message, 1)
+	eb.Subscribe(topics.Misbehavior, eventbus.NewChanListener(misbehaviors))
+
+	for _, b := range branch {
+		_, err = c.ProcessBlockFromNetwork("10.0.0.1:7000", message.New(topics.Block, *b))
+		assert.NoError(err)
+	}
+
+	// The invalid block is held against the peer and it is not tracked
+	msg := <-misbehaviors
+	assert.Equal(scoring.InvalidBlock, msg.Payload().(scoring.Misbehavior).Score)
+	assert.Empty(misbehaviors)
+
+	assert.True(bytes.Equal(local[2].Header.Hash, c.tip.Header.Hash))
+
+	for _, b := range local {
+		assert.True(blockExists(c, b))
+	}
+
+	for i, b := range branch {
+		assert.False(blockExists(c, b))
+		assert.Equal(i == 0, c.forks.has(b.Header.Hash))
+	}
+}
+
+func TestForkBlockUnknownParent(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	checkBlockCertificate = func(user.Provisioners, block.Block, []byte) error {
+		return nil
+	}
+
+	_, c := setupForkTest(t)
+	local := acceptForkBlocks(t, c, 2)
+
+	// A block following an unknown block cannot be verified
+	orphan := forkBlock(t, helper.RandomBlock(local[0].Header.Height, 1), 3)
+
+	_, err := c.ProcessBlockFromNetwork("10.0.0.1:7000", message.New(topics.Block, *orphan))
+	assert.NoError(err)
+	assert.False(c.forks.has(orphan.Header.Hash))
+	assert.True(bytes.Equal(local[1].Header.Hash, c.tip.Header.Hash))
+}
+
+func TestForkBlockPastEpoch(t *testing.T) {
+	assert := assert.New(t)
+	_, c := setupForkTest(t)
+
+	tip := c.tip.Copy().(block.Block)
+	tip.Header.Height = config.EPOCH
+	c.tip = &tip
+
+	// The provisioners of a past epoch are unknown
+	prev := helper.RandomBlock(config.EPOCH-1, 1)
+	err := c.verifyForkBlock(*forkBlock(t, prev, 3), *prev)
+	assert.True(errors.Is(err, errForkPastEpoch))
+	assert.False(isInvalidBlock(err))
+}
+
+func TestReorgRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	checkBlockCertificate = func(user.Provisioners, block.Block, []byte) error {
+		return nil
+	}
+
+	_, c := setupForkTest(t)
+	var err error
+
+	local := acceptForkBlocks(t, c, 3)
+
+	// The state transition of the second block of the branch fails
+	e := &failingExecutor{
+		forkExecutor: c.proxy.Executor().(*forkExecutor),
+		failAt:       local[2].Header.Height,
+	}
+	c.proxy = &transactions.MockProxy{E: e}
+
+	branch := make([]*block.Block, 3)
+	prev := local[0]
+
+	for i := range branch {
+		branch[i] = forkBlock(t, prev, 6)
+		prev = branch[i]
+	}
+
+	for _, b := range branch {
+		_, err = c.ProcessBlockFromNetwork("", message.New(topics.Block, *b))
+		assert.NoError(err)
+	}
+
+	// The local blockchain is restored
+	assert.True(bytes.Equal(local[2].Header.Hash, c.tip.Header.Hash))
+
+	for _, b := range local {
+		assert.True(blockExists(c, b))
+	}
+
+	for _, b := range branch {
+		assert.False(blockExists(c, b))
+	}
+}
+
+// failingExecutor fails the state transition of the first block accepted at
+// the failAt height.
+type failingExecutor struct {
+	*forkExecutor
+	failAt uint64
+}
+
+func (e *failingExecutor) Accept(ctx context.Context, calls []transactions.ContractCall, stateRoot []byte, height, gasLimit uint64, generator []byte, p *user.Provisioners) ([]transactions.ContractCall, user.Provisioners, []byte, error) {
+	if height == e.failAt {
+		e.failAt = 0
+		return nil, user.Provisioners{}, nil, errors.New("state transition failed")
+	}
+
+	return e.forkExecutor.Accept(ctx, calls, stateRoot, height, gasLimit, generator, p)
+}
+
+// forkGenerator generates all mocked fork blocks.
+var forkGenerator = key.NewRandKeys().BLSPubKey
+
+// forkExecutor mocks Rusk for reorgs. Provisioners include the generator of
+// fork blocks.
+type forkExecutor struct {
+	*transactions.PermissiveExecutor
+}
+
+func newForkExecutor() *forkExecutor {
+	e := transactions.MockExecutor(1)
+	_ = e.P.Add(forkGenerator, 1, 1, 1, 0)
+
+	return &forkExecutor{PermissiveExecutor: e}
+}
+
+// setupForkTest creates a chain at the genesis state, backed by a
+// forkExecutor.
+func setupForkTest(t *testing.T) (*eventbus.EventBus, *Chain) {
+	eb, c := setupChainTest(t, 1)
+
+	e := newForkExecutor()
+	c.proxy = &transactions.MockProxy{E: e}
+	c.p = e.P
+
+	// Start from a clean state, whatever the previous tests stored.
+	_, err := c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.NoError(t, err)
+
+	return eb, c
+}
+
+// acceptForkBlocks accepts n blocks from the second consensus iteration on
+// top of the chain tip.
+func acceptForkBlocks(t *testing.T, c *Chain, n int) []*block.Block {
+	blocks := make([]*block.Block, n)
+	prev := c.tip
+
+	for i := range blocks {
+		blocks[i] = forkBlock(t, prev, 6)
+		assert.NoError(t, c.acceptBlock(*blocks[i], true))
+		prev = blocks[i]
+	}
+
+	return blocks
+}
+
+func blockExists(c *Chain, b *block.Block) bool {
+	var found bool
+
+	_ = c.db.View(func(t database.Transaction) error {
+		found, _ = t.FetchBlockExists(b.Header.Hash)
+		return nil
+	})
+
+	return found
+}
+
+// forkBlock creates a valid successor of prev, agreed at the certificate step.
+func forkBlock(t *testing.T, prev *block.Block, step uint8) *block.Block {
+	b := helper.RandomBlock(prev.Header.Height+1, 1)
+	b.Header.PrevBlockHash = prev.Header.Hash
+	b.Header.StateHash = make([]byte, 32)
+	b.Header.GeneratorBlsPubkey = forkGenerator
+	b.Header.Certificate = block.EmptyCertificate()
+	b.Header.Certificate.Step = step
+
+	hash, err := b.CalculateHash()
+	assert.NoError(t, err)
+
+	b.Header.Hash = hash
+	return b
+}
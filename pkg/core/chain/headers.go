@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/verifiers"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+)
+
+var errEmptyHeaders = errors.New("empty headers")
+
+// ProcessHeadersFromNetwork handles the headers requested when entering
+// outSync state. The header chain is verified before any block is requested,
+// so that a dishonest peer is detected without running any state transition.
+// Satisfies the peer.ProcessorFunc interface.
+func (c *Chain) ProcessHeadersFromNetwork(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
+	headers := m.Payload().(message.Headers).Headers
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	defer c.publishSyncProgress()
+
+	if !c.synchronizer.awaitsHeaders(srcPeerID) {
+		log.WithField("r_addr", srcPeerID).Debug("discard unrequested headers")
+		return nil, nil
+	}
+
+	if err := c.verifyHeaders(headers); err != nil {
+		log.WithField("r_addr", srcPeerID).WithError(err).
+			Warn("syncing peer provided invalid headers")
+
+		scoring.Report(c.eventBus, srcPeerID, scoring.InvalidHeaders, err.Error())
+
+		c.synchronizer.dropSync()
+		return nil, err
+	}
+
+	log.WithField("r_addr", srcPeerID).
+		WithField("count", len(headers)).
+		Info("syncing peer provided valid headers")
+
+	return c.synchronizer.requestBlocks(srcPeerID, c.tip.Header.Height, headers)
+}
+
+// verifyHeaders ensures headers form a valid chain following the chain tip,
+// that they do not conflict with any checkpoint, and that their certificates
+// are valid.
+//
+// The certificates are checked against the current provisioners set, which is
+// only known to be in force until the end of the current epoch. Certificates
+// of headers beyond it are checked on block acceptance instead.
+func (c *Chain) verifyHeaders(headers []*block.Header) error {
+	if len(headers) == 0 {
+		return errEmptyHeaders
+	}
+
+	prev := *c.tip
+	epoch := c.tip.Header.Height / config.EPOCH
+
+	for _, header := range headers {
+		blk := block.Block{Header: header}
+
+		if err := c.checkpoints.check(header); err != nil {
+			return err
+		}
+
+		if err := verifiers.CheckBlockHeader(prev, blk); err != nil {
+			return fmt.Errorf("header at height %d: %w", header.Height, err)
+		}
+
+		// The provisioners set is updated once the first block of an epoch
+		// is accepted
+		if prev.Header.Height/config.EPOCH == epoch {
+			if err := checkBlockCertificate(*c.p, blk, prev.Header.Seed); err != nil {
+				return fmt.Errorf("certificate at height %d: %w", header.Height, err)
+			}
+		}
+
+		prev = blk
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestHeaderFirstSync(t *testing.T) {
+	assert := assert.New(t)
+
+	// Certificates of mocked blocks are not valid
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	checkBlockCertificate = func(user.Provisioners, block.Block, []byte) error {
+		return nil
+	}
+
+	_, c := setupChainTest(t, 1)
+
+	// Start from a clean state, whatever the previous tests stored.
+	_, err := c.RebuildChain(context.Background(), &node.EmptyRequest{})
+	assert.NoError(err)
+
+	defer c.synchronizer.cancelRanges()
+
+	blocks := make([]*block.Block, 3)
+	prev := c.tip
+
+	for i := range blocks {
+		blocks[i] = forkBlock(t, prev, 3)
+		prev = blocks[i]
+	}
+
+	headers := func(blocks ...*block.Block) message.Message {
+		msg := message.Headers{}
+		for _, b := range blocks {
+			msg.Headers = append(msg.Headers, b.Header)
+		}
+
+		return message.New(topics.Headers, msg)
+	}
+
+	// A future block makes the chain to request headers first
+	resp, err := c.ProcessBlockFromNetwork("peer_a", message.New(topics.Block, *blocks[2]))
+	assert.NoError(err)
+	assert.Equal(uint8(topics.GetHeaders), resp[0].Bytes()[0])
+	assert.True(c.synchronizer.awaitsHeaders("peer_a"))
+
+	// Headers not linked to each other are rejected, and sync is dropped
+	_, err = c.ProcessHeadersFromNetwork("peer_a", headers(blocks[0], blocks[2]))
+	assert.Error(err)
+	assert.False(c.synchronizer.awaitsHeaders("peer_a"))
+
+	resp, err = c.ProcessBlockFromNetwork("peer_a", message.New(topics.Block, *blocks[2]))
+	assert.NoError(err)
+	assert.Equal(uint8(topics.GetHeaders), resp[0].Bytes()[0])
+
+	// Headers are only accepted from the peer they were requested from
+	resp, err = c.ProcessHeadersFromNetwork("peer_b", headers(blocks...))
+	assert.NoError(err)
+	assert.Nil(resp)
+	assert.True(c.synchronizer.awaitsHeaders("peer_a"))
+
+	// Blocks are requested once headers are verified
+	resp, err = c.ProcessHeadersFromNetwork("peer_a", headers(blocks...))
+	assert.NoError(err)
+	assert.Len(resp, 1)
+	assert.Equal(uint8(topics.GetBlockRange), resp[0].Bytes()[0])
+	assert.Len(c.synchronizer.verified, 3)
+	assert.Equal(uint64(3), c.synchronizer.hrange.to)
+}
+
+// TestHeadersNotProvided ensures a peer which does not provide the headers in
+// time is scored, and the headers are requested from another known peer.
+func TestHeadersNotProvided(t *testing.T) {
+	assert := assert.New(t)
+
+	eb, c := setupForkTest(t)
+	defer c.synchronizer.cancelRanges()
+
+	misbehaviors := make(chan message.Message, 2)
+	eb.Subscribe(topics.Misbehavior, eventbus.NewChanListener(misbehaviors))
+
+	requests := make(chan message.Message, 1)
+	eb.Subscribe(topics.KadcastSendToOne, eventbus.NewChanListener(requests))
+
+	c.synchronizer.addPeer("peer_b")
+
+	blk := forkBlock(t, forkBlock(t, forkBlock(t, c.tip, 3), 3), 3)
+
+	resp, err := c.ProcessBlockFromNetwork("peer_a", message.New(topics.Block, *blk))
+	assert.NoError(err)
+	assert.Equal(uint8(topics.GetHeaders), resp[0].Bytes()[0])
+
+	assert.NoError(c.ProcessSyncTimerExpired("peer_a"))
+	assert.Equal(scoring.SyncTimeout, (<-misbehaviors).Payload().(scoring.Misbehavior).Score)
+
+	// Headers are requested from the other peer, blocks are not requested
+	msg := <-requests
+	assert.Equal("peer_b", msg.Metadata().Source)
+	assert.True(c.synchronizer.awaitsHeaders("peer_b"))
+	assert.Empty(c.synchronizer.ranges)
+
+	// Sync is dropped once no other peer is known
+	assert.NoError(c.ProcessSyncTimerExpired("peer_b"))
+	assert.Equal(scoring.SyncTimeout, (<-misbehaviors).Payload().(scoring.Misbehavior).Score)
+	assert.False(c.synchronizer.awaitsHeaders("peer_b"))
+	assert.False(c.synchronizer.syncing)
+}
+
+// TestVerifyHeadersEpoch ensures certificates are only checked against the
+// current provisioners set until the end of the current epoch.
+func TestVerifyHeadersEpoch(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(f func(user.Provisioners, block.Block, []byte) error) {
+		checkBlockCertificate = f
+	}(checkBlockCertificate)
+
+	checked := make([]uint64, 0)
+	checkBlockCertificate = func(_ user.Provisioners, b block.Block, _ []byte) error {
+		checked = append(checked, b.Header.Height)
+		return nil
+	}
+
+	// The chain tip is two blocks behind the next epoch
+	tip := forkBlock(t, &block.Block{Header: &block.Header{Height: config.EPOCH - 3}}, 1)
+	c := &Chain{tip: tip, p: user.NewProvisioners(), checkpoints: checkpoints{}}
+
+	headers := make([]*block.Header, 4)
+	prev := tip
+
+	for i := range headers {
+		b := forkBlock(t, prev, 1)
+		headers[i] = b.Header
+		prev = b
+	}
+
+	assert.NoError(c.verifyHeaders(headers))
+
+	// The first block of the next epoch is the last one checked
+	assert.Equal([]uint64{config.EPOCH - 1, config.EPOCH}, checked)
+
+	// A header failing the certificate check within the epoch is rejected
+	checkBlockCertificate = func(user.Provisioners, block.Block, []byte) error {
+		return errors.New("invalid certificate")
+	}
+
+	assert.Error(c.verifyHeaders(headers))
+}
@@ -91,7 +91,6 @@ func eventConsumer(event <-chan time.Time, cancelChan chan bool, onExpiredFn fun
 	case <-cancelChan:
 		return
 	case <-event:
-		// TODO: Increase ban score for the dishonest Peer
 		// Trigger callback
 		if err := onExpiredFn(strPeerAddr); err != nil {
 			logrus.WithError(err).Warn("outsynctimer expiry callback err")
@@ -0,0 +1,212 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/rusk"
+	"google.golang.org/protobuf/proto"
+)
+
+var errReplayState = errors.New("rusk state does not match the block preceding the replay")
+
+// ReplayDivergence describes a difference between the outcome of a replayed
+// block and the one stored in the chain database.
+type ReplayDivergence struct {
+	Height   uint64 `json:"height"`
+	TxID     string `json:"txid,omitempty"`
+	Field    string `json:"field"`
+	Stored   string `json:"stored"`
+	Replayed string `json:"replayed"`
+}
+
+// ReplayReport is the result of a blocks replay.
+type ReplayReport struct {
+	From           uint64             `json:"from"`
+	To             uint64             `json:"to"`
+	ReplayedBlocks uint64             `json:"replayed_blocks"`
+	Divergences    []ReplayDivergence `json:"divergences"`
+}
+
+func (r *ReplayReport) add(height uint64, txid []byte, field, stored, replayed string) {
+	d := ReplayDivergence{
+		Height:   height,
+		Field:    field,
+		Stored:   stored,
+		Replayed: replayed,
+	}
+
+	if len(txid) > 0 {
+		d.TxID = hex.EncodeToString(txid)
+	}
+
+	r.Divergences = append(r.Divergences, d)
+}
+
+// Replay drives the blocks in the height range [from, to] of the chain
+// database through the state transitions of the executor, the same way they
+// were accepted. The produced state roots, gas spent and transaction errors
+// are compared with the ones stored.
+//
+// The executor state must be the one of the block preceding the range. As
+// blocks are accepted and finalized, the executor state is modified: it should
+// never be the one of a running node. The replay stops at the first block
+// producing a different state root, as the following ones can not be replayed
+// on top of it.
+func Replay(ctx context.Context, db database.DB, executor transactions.Executor, from, to uint64) (*ReplayReport, error) {
+	if from == 0 || from > to {
+		return nil, fmt.Errorf("invalid range %d-%d", from, to)
+	}
+
+	report := &ReplayReport{From: from, To: to, Divergences: make([]ReplayDivergence, 0)}
+
+	prev, err := fetchBlockAt(db, from-1)
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %w", from-1, err)
+	}
+
+	stateRoot, err := executor.GetStateRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(stateRoot, prev.Header.StateHash) {
+		return nil, fmt.Errorf("%w: rusk %s, block %d %s", errReplayState, hex.EncodeToString(stateRoot),
+			prev.Header.Height, hex.EncodeToString(prev.Header.StateHash))
+	}
+
+	provisioners, err := executor.GetProvisioners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for height := from; height <= to; height++ {
+		blk, err := fetchBlockAt(db, height)
+		if err != nil {
+			return report, fmt.Errorf("block %d: %w", height, err)
+		}
+
+		stateRoot, err = executor.VerifyStateTransition(ctx, blk.Txs, blk.Header.GasLimit,
+			blk.Header.Height, blk.Header.GeneratorBlsPubkey)
+
+		switch {
+		case err != nil:
+			report.add(height, nil, "verify_state_transition", "", err.Error())
+		case !bytes.Equal(stateRoot, blk.Header.StateHash):
+			report.add(height, nil, "verify_state_root", hex.EncodeToString(blk.Header.StateHash), hex.EncodeToString(stateRoot))
+		}
+
+		var txs []transactions.ContractCall
+
+		// Same as Chain.runStateTransition
+		if blk.Header.Certificate.Step == 3 {
+			txs, provisioners, stateRoot, err = executor.Finalize(ctx, blk.Txs, prev.Header.StateHash,
+				blk.Header.Height, blk.Header.GasLimit, blk.Header.GeneratorBlsPubkey, &provisioners)
+		} else {
+			txs, provisioners, stateRoot, err = executor.Accept(ctx, blk.Txs, prev.Header.StateHash,
+				blk.Header.Height, blk.Header.GasLimit, blk.Header.GeneratorBlsPubkey, &provisioners)
+		}
+
+		if err != nil {
+			return report, fmt.Errorf("block %d: %w", height, err)
+		}
+
+		report.ReplayedBlocks++
+
+		compareExecutedTxs(report, blk, txs)
+
+		if !bytes.Equal(stateRoot, blk.Header.StateHash) {
+			report.add(height, nil, "state_hash", hex.EncodeToString(blk.Header.StateHash), hex.EncodeToString(stateRoot))
+			return report, nil
+		}
+
+		prev = blk
+	}
+
+	return report, nil
+}
+
+// compareExecutedTxs reports the transactions executed differently from the
+// stored ones.
+func compareExecutedTxs(report *ReplayReport, blk *block.Block, executed []transactions.ContractCall) {
+	height := blk.Header.Height
+	stored := make(map[string]transactions.ContractCall, len(blk.Txs))
+
+	for _, tx := range blk.Txs {
+		if h, err := tx.CalculateHash(); err == nil {
+			stored[string(h)] = tx
+		}
+	}
+
+	for _, tx := range executed {
+		h, err := tx.CalculateHash()
+		if err != nil {
+			continue
+		}
+
+		s, ok := stored[string(h)]
+		if !ok {
+			report.add(height, h, "tx", "", "executed")
+			continue
+		}
+
+		delete(stored, string(h))
+
+		if s.GasSpent() != tx.GasSpent() {
+			report.add(height, h, "gas_spent", strconv.FormatUint(s.GasSpent(), 10), strconv.FormatUint(tx.GasSpent(), 10))
+		}
+
+		if !sameTxError(s.TxError(), tx.TxError()) {
+			report.add(height, h, "tx_error", txErrorString(s.TxError()), txErrorString(tx.TxError()))
+		}
+	}
+
+	for h := range stored {
+		report.add(height, []byte(h), "tx", "included", "")
+	}
+}
+
+func sameTxError(a, b *rusk.ExecutedTransaction_Error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return proto.Equal(a, b)
+}
+
+func txErrorString(e *rusk.ExecutedTransaction_Error) string {
+	if e == nil {
+		return ""
+	}
+
+	return e.String()
+}
+
+func fetchBlockAt(db database.DB, height uint64) (*block.Block, error) {
+	var blk *block.Block
+
+	err := db.View(func(t database.Transaction) error {
+		hash, err := t.FetchBlockHashByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		blk, err = t.FetchBlock(hash)
+		return err
+	})
+
+	return blk, err
+}
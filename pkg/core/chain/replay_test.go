@@ -0,0 +1,141 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	assert "github.com/stretchr/testify/require"
+)
+
+// replayExecutor reproduces the state transitions of a chain of blocks.
+type replayExecutor struct {
+	*transactions.PermissiveExecutor
+
+	blocks map[uint64]*block.Block
+	root   []byte
+
+	// gasDelta is added to the gas spent by the txs of a height.
+	gasDelta map[uint64]uint64
+	// diverging is the height producing a wrong state root.
+	diverging uint64
+}
+
+func (e *replayExecutor) GetStateRoot(context.Context) ([]byte, error) {
+	return e.root, nil
+}
+
+func (e *replayExecutor) VerifyStateTransition(_ context.Context, _ []transactions.ContractCall, _, height uint64, _ []byte) ([]byte, error) {
+	return e.stateRoot(height), nil
+}
+
+func (e *replayExecutor) Accept(_ context.Context, txs []transactions.ContractCall, _ []byte, height, _ uint64, _ []byte, _ *user.Provisioners) ([]transactions.ContractCall, user.Provisioners, []byte, error) {
+	executed := make([]transactions.ContractCall, len(txs))
+
+	for i, tx := range txs {
+		updated, err := transactions.UpdateTransaction(tx, tx.GasSpent()+e.gasDelta[height], tx.TxError())
+		if err != nil {
+			return nil, user.Provisioners{}, nil, err
+		}
+
+		executed[i] = updated
+	}
+
+	e.root = e.stateRoot(height)
+	return executed, *e.P, e.root, nil
+}
+
+func (e *replayExecutor) Finalize(ctx context.Context, txs []transactions.ContractCall, root []byte, height, gasLimit uint64, generator []byte, p *user.Provisioners) ([]transactions.ContractCall, user.Provisioners, []byte, error) {
+	return e.Accept(ctx, txs, root, height, gasLimit, generator, p)
+}
+
+func (e *replayExecutor) stateRoot(height uint64) []byte {
+	if height == e.diverging {
+		return make([]byte, 32)
+	}
+
+	return e.blocks[height].Header.StateHash
+}
+
+func TestReplay(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	blocks := linkedBlocks(t, 5)
+
+	// Each block has its own state root
+	for i, b := range blocks {
+		b.Header.StateHash = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		for _, b := range blocks {
+			if err := t.StoreBlock(b, true); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	newExecutor := func() *replayExecutor {
+		e := &replayExecutor{
+			PermissiveExecutor: transactions.MockExecutor(0),
+			blocks:             make(map[uint64]*block.Block),
+			root:               blocks[0].Header.StateHash,
+			gasDelta:           make(map[uint64]uint64),
+		}
+
+		for _, b := range blocks {
+			e.blocks[b.Header.Height] = b
+		}
+
+		return e
+	}
+
+	// Same outcome as the stored one
+	report, err := Replay(context.Background(), db, newExecutor(), 1, 4)
+	assert.NoError(err)
+	assert.Empty(report.Divergences)
+	assert.Equal(uint64(4), report.ReplayedBlocks)
+
+	// Rusk state differs from the block preceding the range
+	_, err = Replay(context.Background(), db, newExecutor(), 2, 4)
+	assert.Error(err)
+
+	// Transactions spend a different amount of gas
+	e := newExecutor()
+	e.gasDelta[2] = 1
+
+	report, err = Replay(context.Background(), db, e, 1, 4)
+	assert.NoError(err)
+	assert.NotEmpty(report.Divergences)
+
+	for _, d := range report.Divergences {
+		assert.Equal(uint64(2), d.Height)
+		assert.Equal("gas_spent", d.Field)
+	}
+
+	// The replay stops at the first diverging state root
+	e = newExecutor()
+	e.diverging = 3
+
+	report, err = Replay(context.Background(), db, e, 1, 4)
+	assert.NoError(err)
+	assert.Equal(uint64(3), report.ReplayedBlocks)
+
+	last := report.Divergences[len(report.Divergences)-1]
+	assert.Equal("state_hash", last.Field)
+	assert.Equal(uint64(3), last.Height)
+}
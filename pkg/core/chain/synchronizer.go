@@ -9,22 +9,50 @@ package chain
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	syncTimeout      = time.Duration(5) * time.Second
 	changeStatelabel = "change state"
+
+	// syncRangeSize is the number of blocks requested from a single peer.
+	syncRangeSize = 100
+	// maxSyncRetries is the number of times a range is requested again
+	// before giving up on the sync procedure.
+	maxSyncRetries = 3
+	// maxSyncPeers is the number of peers remembered as sources of blocks.
+	maxSyncPeers = 16
 )
 
-var slog = logrus.WithField("process", "sync")
+var (
+	slog = logrus.WithField("process", "sync")
+
+	errSyncRetriesExceeded = errors.New("sync range retries exceeded")
+	errHeadersNotProvided  = errors.New("sync headers not provided")
+	errUnverifiedBlock     = errors.New("block does not match the verified header")
+)
+
+// syncRange is a range of blocks requested from a single peer. Its timer is
+// reset each time the peer provides a block of the range, and expires if the
+// peer stops providing them.
+type syncRange struct {
+	from    uint64
+	to      uint64
+	peer    string
+	retries int
+	timer   *outSyncTimer
+}
 
 type syncState func(srcPeerAddr string, currentHeight uint64, blk block.Block, metadata *message.Metadata) ([]bytes.Buffer, error)
 
@@ -34,15 +62,8 @@ func (s *synchronizer) inSync(srcPeerAddr string, currentHeight uint64, blk bloc
 
 		slog.WithField("state", "outsync").Debug(changeStatelabel)
 
-		// Trigger timeout outSync timer. If the peer initiating the Sync
-		// procedure is dishonest, this timer should switch back to InSync
-		// and restart Consensus Loop.
-		//
-		// A peer is marked as dishonest if it cannot provide a valid
-		// consecutive block before the timer expires
-		s.timer.Start(srcPeerAddr)
-
 		s.state = s.outSync
+		s.syncing = true
 		b, err := s.startSync(srcPeerAddr, blk.Header.Height, currentHeight, metadata)
 		return b, err
 	}
@@ -54,6 +75,11 @@ func (s *synchronizer) inSync(srcPeerAddr string, currentHeight uint64, blk bloc
 			WithField("state", "insync").
 			WithError(err).
 			Warn("could not AcceptBlock")
+
+		if isInvalidBlock(err) {
+			scoring.Report(s.publisher, srcPeerAddr, scoring.InvalidBlock, err.Error())
+		}
+
 		return nil, err
 	}
 
@@ -63,6 +89,18 @@ func (s *synchronizer) inSync(srcPeerAddr string, currentHeight uint64, blk bloc
 func (s *synchronizer) outSync(srcPeerAddr string, currentHeight uint64, blk block.Block, metadata *message.Metadata) ([]bytes.Buffer, error) {
 	var err error
 
+	if s.headerTimer != nil {
+		// Headers are not verified yet. The block is queued until then.
+		s.sequencer.add(blk)
+		return nil, nil
+	}
+
+	if hash, ok := s.verified[blk.Header.Height]; ok && !bytes.Equal(hash, blk.Header.Hash) {
+		// The block is not part of the verified header chain
+		scoring.Report(s.publisher, srcPeerAddr, scoring.InvalidBlock, errUnverifiedBlock.Error())
+		return nil, errUnverifiedBlock
+	}
+
 	// Once we validate successfully the next block from the syncing
 	// Peer we can consider terminating Consensus for efficiency
 	// purposes.
@@ -71,12 +109,14 @@ func (s *synchronizer) outSync(srcPeerAddr string, currentHeight uint64, blk blo
 		// This validation should happen only once to ensure we can trust
 		// this peer for syncing up
 		if err = s.chain.TryNextConsecutiveBlockIsValid(blk); err != nil {
-			if srcPeerAddr == s.timer.ownerID {
+			if isInvalidBlock(err) {
+				scoring.Report(s.publisher, srcPeerAddr, scoring.InvalidBlock, err.Error())
+			}
+
+			if r := s.rangeOf(blk.Header.Height); r != nil && srcPeerAddr == r.peer {
 				// Syncing Peer has provided invalid next block
 				slog.WithField("r_addr", srcPeerAddr).Warn("syncing peer provided invalid next block")
-				slog.WithField("state", "insync").Debug(changeStatelabel)
-
-				s.state = s.inSync
+				s.dropSync()
 			}
 
 			return nil, err
@@ -87,12 +127,22 @@ func (s *synchronizer) outSync(srcPeerAddr string, currentHeight uint64, blk blo
 		s.chain.StopConsensus()
 	}
 
+	if r := s.rangeOf(blk.Header.Height); r != nil {
+		// Peer does provide a block of its range. Its outSyncTimer should
+		// restart its counter
+		if err = r.timer.Reset(srcPeerAddr); err != nil {
+			slog.WithError(err).WithField("state", "outsync").
+				Trace("timer error")
+		}
+	}
+
 	if blk.Header.Height > currentHeight+1 {
 		// if there is a gap we add the future block to the sequencer
 		s.sequencer.add(blk)
 
 		blk, err = s.sequencer.get(currentHeight + 1)
 		if err != nil {
+			s.completeRanges(currentHeight)
 			return nil, nil
 		}
 	}
@@ -109,16 +159,11 @@ func (s *synchronizer) outSync(srcPeerAddr string, currentHeight uint64, blk blo
 			return nil, err
 		}
 
-		// Peer does provide a valid consecutive block
-		// outSyncTimer should restart its counter
-		if err = s.timer.Reset(srcPeerAddr); err != nil {
-			slog.WithError(err).WithField("state", "outsync").
-				Warn("timer error")
-		}
+		currentHeight = blk.Header.Height
 
 		if blk.Header.Height == s.hrange.to {
-			// Sync Target reached. outSyncTimer is not anymore needed
-			s.timer.Cancel()
+			// Sync Target reached. Range timers are not anymore needed
+			s.cancelRanges()
 
 			// if we reach the target we get into sync mode
 			// and trigger the consensus again
@@ -129,10 +174,12 @@ func (s *synchronizer) outSync(srcPeerAddr string, currentHeight uint64, blk blo
 			slog.WithField("state", "insync").Debug(changeStatelabel)
 
 			s.state = s.inSync
-			break
+			s.syncing = false
+			return nil, nil
 		}
 	}
 
+	s.completeRanges(currentHeight)
 	return nil, nil
 }
 
@@ -144,26 +191,41 @@ type synchronizer struct {
 	db    database.DB
 	state syncState
 	*sequencer
-	chain Ledger
+	chain     Ledger
+	publisher eventbus.Publisher
 
 	hrange struct {
 		from uint64
 		to   uint64
 	}
 
-	timer *outSyncTimer
+	// syncing is true in outSync state. syncPeer is the address of the peer
+	// which triggered the sync procedure.
+	syncing  bool
+	syncPeer string
+
+	// headerTimer is set while the headers requested during outSync state
+	// are awaited. headerRetries is the number of times they were requested
+	// from another peer.
+	headerTimer   *outSyncTimer
+	headerRetries int
+	// verified maps the heights of the verified headers to their hashes.
+	verified map[uint64][]byte
+	// ranges are the ranges of blocks requested during outSync state.
+	ranges []*syncRange
+	// peers are the addresses of the peers which recently provided blocks.
+	peers []string
 }
 
 // newSynchronizer returns an initialized synchronizer, ready for use.
-func newSynchronizer(db database.DB, chain Ledger) *synchronizer {
+func newSynchronizer(db database.DB, chain Ledger, publisher eventbus.Publisher) *synchronizer {
 	s := &synchronizer{
 		db:        db,
 		sequencer: newSequencer(),
 		chain:     chain,
+		publisher: publisher,
 	}
 
-	s.timer = newSyncTimer(syncTimeout, chain.ProcessSyncTimerExpired)
-
 	slog.WithField("state", "insync").Debug(changeStatelabel)
 
 	s.state = s.inSync
@@ -176,11 +238,17 @@ func (s *synchronizer) processBlock(srcPeerID string, currentHeight uint64, blk
 	s.sequencer.cleanup(currentHeight)
 	s.sequencer.dump()
 
+	s.addPeer(srcPeerID)
+
 	currState := s.state
 	res, err = currState(srcPeerID, currentHeight, blk, metadata)
 	return
 }
 
+// startSync requests the headers between currentHeight and the sync target
+// from strPeerAddr. The request is returned, so that it is sent back as a
+// response. Blocks are requested once the headers are verified. See
+// requestBlocks.
 func (s *synchronizer) startSync(strPeerAddr string, tipHeight, currentHeight uint64, _ *message.Metadata) ([]bytes.Buffer, error) {
 	s.hrange.from = currentHeight
 	s.setSyncTarget(tipHeight, currentHeight+config.MaxInvBlocks)
@@ -191,18 +259,293 @@ func (s *synchronizer) startSync(strPeerAddr string, tipHeight, currentHeight ui
 		WithField("r_addr", strPeerAddr).
 		Info("start syncing")
 
-	var hash []byte
+	s.cancelRanges()
+	s.verified = nil
+	s.syncPeer = strPeerAddr
 
-	if err := s.db.View(func(t database.Transaction) error {
-		var err error
-		hash, err = t.FetchBlockHashByHeight(currentHeight)
-		return err
-	}); err != nil {
+	// If the peer cannot provide the headers before the timer expires, they
+	// are requested from another peer. See retryHeaders.
+	s.headerRetries = 0
+	s.headerTimer = newSyncTimer(syncTimeout, s.chain.ProcessSyncTimerExpired)
+	s.headerTimer.Start(strPeerAddr)
+
+	return marshalGetBlockRange(topics.GetHeaders, &message.GetBlockRange{From: currentHeight + 1, To: s.hrange.to})
+}
+
+// awaitsHeaders returns true if headers are awaited from srcPeerAddr.
+func (s *synchronizer) awaitsHeaders(srcPeerAddr string) bool {
+	return s.headerTimer != nil && s.headerTimer.ownerID == srcPeerAddr
+}
+
+// requestBlocks records the verified headers, and splits them into ranges of
+// blocks requested concurrently from the known peers. The requests to
+// srcPeerAddr are returned, so that they are sent back as a response.
+func (s *synchronizer) requestBlocks(srcPeerAddr string, currentHeight uint64, headers []*block.Header) ([]bytes.Buffer, error) {
+	s.headerTimer.Cancel()
+	s.headerTimer = nil
+
+	s.verified = make(map[uint64][]byte, len(headers))
+	for _, header := range headers {
+		s.verified[header.Height] = header.Hash
+	}
+
+	// The sync target can not exceed the headers provided
+	s.hrange.to = headers[len(headers)-1].Height
+
+	// Drop the queued blocks which are not part of the verified header chain
+	for height, hash := range s.verified {
+		if blk, err := s.sequencer.get(height); err == nil && !bytes.Equal(blk.Header.Hash, hash) {
+			s.sequencer.remove(height)
+		}
+	}
+
+	// The peer initiating the sync procedure is asked for the first range
+	peers := []string{srcPeerAddr}
+
+	for _, peer := range s.peers {
+		if peer != srcPeerAddr {
+			peers = append(peers, peer)
+		}
+	}
+
+	var res []bytes.Buffer
+
+	for from := currentHeight + 1; from <= s.hrange.to; from += syncRangeSize {
+		r := &syncRange{
+			from:  from,
+			to:    from + syncRangeSize - 1,
+			peer:  peers[len(s.ranges)%len(peers)],
+			timer: newSyncTimer(syncTimeout, s.chain.ProcessSyncTimerExpired),
+		}
+
+		if r.to > s.hrange.to {
+			r.to = s.hrange.to
+		}
+
+		s.ranges = append(s.ranges, r)
+
+		buf, err := s.requestRange(r, srcPeerAddr)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, buf...)
+	}
+
+	return res, nil
+}
+
+// dropSync cancels the pending requests, and switches back to inSync state.
+func (s *synchronizer) dropSync() {
+	s.cancelRanges()
+
+	slog.WithField("state", "insync").Debug(changeStatelabel)
+
+	s.state = s.inSync
+	s.syncing = false
+}
+
+// requestRange requests the blocks of r from its peer, and starts the range
+// timer. If the peer is srcPeerAddr, the request is returned. Otherwise it is
+// sent point-to-point.
+func (s *synchronizer) requestRange(r *syncRange, srcPeerAddr string) ([]bytes.Buffer, error) {
+	// Trigger timeout outSync timer. If the peer is dishonest, this timer
+	// should make the range to be requested from another peer.
+	//
+	// A peer is marked as dishonest if it cannot provide a block of its
+	// range before the timer expires
+	r.timer.Start(r.peer)
+
+	slog.WithField("from", r.from).
+		WithField("to", r.to).
+		WithField("r_addr", r.peer).
+		WithField("retries", r.retries).
+		Debug("request range")
+
+	bufs, err := s.rangeRequest(r)
+	if err != nil {
 		return nil, err
 	}
 
-	msgGetBlocks := createGetBlocksMsg(hash)
-	return marshalGetBlocks(msgGetBlocks)
+	if r.peer == srcPeerAddr {
+		return bufs, nil
+	}
+
+	metadata := &message.Metadata{Source: r.peer}
+	s.publisher.Publish(topics.KadcastSendToOne, message.NewWithMetadata(topics.KadcastSendToOne, bufs[0], metadata))
+
+	return nil, nil
+}
+
+// rangeRequest returns the request of the blocks of r. It is a GetBlockRange
+// request at first. As peers running an older protocol version do not answer
+// it, a retried range is requested with a GetBlocks request instead, whose
+// locator is the block preceding the range. Any peer advertises the blocks
+// following the locator in response.
+func (s *synchronizer) rangeRequest(r *syncRange) ([]bytes.Buffer, error) {
+	if r.retries == 0 {
+		return marshalGetBlockRange(topics.GetBlockRange, &message.GetBlockRange{From: r.from, To: r.to})
+	}
+
+	hash, ok := s.verified[r.from-1]
+	if !ok {
+		if err := s.db.View(func(t database.Transaction) error {
+			var err error
+			hash, err = t.FetchBlockHashByHeight(r.from - 1)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return marshalGetBlocks(&message.GetBlocks{Locators: [][]byte{hash}})
+}
+
+// retryRanges requests the pending headers or ranges of an unresponsive peer
+// from another peer. It returns errSyncRetriesExceeded if a range was
+// requested too many times already, and errHeadersNotProvided if no other
+// peer can be asked for the headers.
+func (s *synchronizer) retryRanges(peer string, currentHeight uint64) error {
+	s.removePeer(peer)
+
+	if s.awaitsHeaders(peer) {
+		return s.retryHeaders(currentHeight)
+	}
+
+	for i, r := range s.ranges {
+		if r.peer != peer || r.to <= currentHeight {
+			continue
+		}
+
+		if r.retries >= maxSyncRetries {
+			return errSyncRetriesExceeded
+		}
+
+		if r.from <= currentHeight {
+			r.from = currentHeight + 1
+		}
+
+		r.retries++
+		r.peer = s.nextPeer(peer, i+r.retries)
+
+		if _, err := s.requestRange(r, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retryHeaders requests the headers up to the sync target from the next known
+// peer, and restarts the header timer.
+func (s *synchronizer) retryHeaders(currentHeight uint64) error {
+	if len(s.peers) == 0 || s.headerRetries >= maxSyncRetries {
+		return errHeadersNotProvided
+	}
+
+	peer := s.nextPeer("", s.headerRetries)
+	s.headerRetries++
+
+	slog.WithField("r_addr", peer).Debug("request headers")
+
+	bufs, err := marshalGetBlockRange(topics.GetHeaders, &message.GetBlockRange{From: currentHeight + 1, To: s.hrange.to})
+	if err != nil {
+		return err
+	}
+
+	s.headerTimer.Start(peer)
+
+	metadata := &message.Metadata{Source: peer}
+	s.publisher.Publish(topics.KadcastSendToOne, message.NewWithMetadata(topics.KadcastSendToOne, bufs[0], metadata))
+
+	return nil
+}
+
+// completeRanges cancels the timers of the ranges whose blocks have all been
+// accepted or queued in the sequencer.
+func (s *synchronizer) completeRanges(currentHeight uint64) {
+	for _, r := range s.ranges {
+		complete := true
+
+		for h := r.from; h <= r.to && complete; h++ {
+			if h <= currentHeight {
+				continue
+			}
+
+			if _, err := s.sequencer.get(h); err != nil {
+				complete = false
+			}
+		}
+
+		if complete {
+			r.timer.Cancel()
+		}
+	}
+}
+
+// cancelRanges cancels the timers of all pending requests and drops the
+// ranges.
+func (s *synchronizer) cancelRanges() {
+	if s.headerTimer != nil {
+		s.headerTimer.Cancel()
+		s.headerTimer = nil
+	}
+
+	for _, r := range s.ranges {
+		r.timer.Cancel()
+	}
+
+	s.ranges = nil
+}
+
+// rangeOf returns the range including height, if any.
+func (s *synchronizer) rangeOf(height uint64) *syncRange {
+	for _, r := range s.ranges {
+		if height >= r.from && height <= r.to {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// addPeer remembers a peer as a source of blocks. The oldest peer is
+// forgotten once maxSyncPeers are known.
+func (s *synchronizer) addPeer(peer string) {
+	if len(peer) == 0 {
+		return
+	}
+
+	for _, p := range s.peers {
+		if p == peer {
+			return
+		}
+	}
+
+	if len(s.peers) == maxSyncPeers {
+		s.peers = s.peers[1:]
+	}
+
+	s.peers = append(s.peers, peer)
+}
+
+func (s *synchronizer) removePeer(peer string) {
+	for i, p := range s.peers {
+		if p == peer {
+			s.peers = append(s.peers[:i], s.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// nextPeer picks the n-th known peer in a round-robin fashion. fallback is
+// returned if no peer is known.
+func (s *synchronizer) nextPeer(fallback string, n int) string {
+	if len(s.peers) == 0 {
+		return fallback
+	}
+
+	return s.peers[n%len(s.peers)]
 }
 
 func (s *synchronizer) setSyncTarget(tipHeight, maxHeight uint64) {
@@ -212,15 +555,18 @@ func (s *synchronizer) setSyncTarget(tipHeight, maxHeight uint64) {
 	}
 }
 
-func createGetBlocksMsg(latestHash []byte) *message.GetBlocks {
-	msg := &message.GetBlocks{}
-	msg.Locators = append(msg.Locators, latestHash)
-	return msg
+func marshalGetBlocks(msg *message.GetBlocks) ([]bytes.Buffer, error) {
+	buf := topics.GetBlocks.ToBuffer()
+	if err := msg.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return []bytes.Buffer{buf}, nil
 }
 
 //nolint:unparam
-func marshalGetBlocks(msg *message.GetBlocks) ([]bytes.Buffer, error) {
-	buf := topics.GetBlocks.ToBuffer()
+func marshalGetBlockRange(topic topics.Topic, msg *message.GetBlockRange) ([]bytes.Buffer, error) {
+	buf := topic.ToBuffer()
 	if err := msg.Encode(&buf); err != nil {
 		// FIXME: shall this panic here ?  result 1 (error) is always nil (unparam)
 		// log.Panic(err)
@@ -7,6 +7,7 @@
 package chain
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config/genesis"
@@ -15,14 +16,16 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	assert "github.com/stretchr/testify/require"
 )
 
 func TestSuccessiveBlocks(t *testing.T) {
 	assert := assert.New(t)
-	s, c := setupSynchronizerTest()
+	s, c, _ := setupSynchronizerTest()
 
 	// tipHeight will be 0, so make the successive block
 	blk := helper.RandomBlock(1, 1)
@@ -37,21 +40,129 @@ func TestSuccessiveBlocks(t *testing.T) {
 
 func TestFutureBlocks(t *testing.T) {
 	assert := assert.New(t)
-	s, _ := setupSynchronizerTest()
+	s, _, _ := setupSynchronizerTest()
 
 	height := uint64(10)
 	blk := helper.RandomBlock(height, 1)
 	resp, err := s.processBlock("", 0, *blk, nil)
 	assert.NoError(err)
 
-	// Response should be of the GetBlocks topic
-	assert.Equal(resp[0].Bytes()[0], uint8(topics.GetBlocks))
+	defer s.cancelRanges()
+
+	// Response should be of the GetHeaders topic
+	assert.Equal(resp[0].Bytes()[0], uint8(topics.GetHeaders))
 
 	// Block should be in the sequencer
 	assert.NotEmpty(s.sequencer.blockPool[height])
 }
 
-func setupSynchronizerTest() (*synchronizer, chan consensus.Results) {
+func TestParallelRanges(t *testing.T) {
+	assert := assert.New(t)
+	s, _, eb := setupSynchronizerTest()
+
+	sent := make(chan message.Message, 10)
+	eb.Subscribe(topics.KadcastSendToOne, eventbus.NewChanListener(sent))
+
+	s.addPeer("peer_b")
+
+	// A block 250 heights ahead makes the gap to be split into 3 ranges
+	blk := helper.RandomBlock(250, 1)
+	_, err := s.processBlock("peer_a", 0, *blk, nil)
+	assert.NoError(err)
+
+	defer s.cancelRanges()
+
+	headers := make([]*block.Header, 250)
+	for i := range headers {
+		headers[i] = block.NewHeader()
+		headers[i].Height = uint64(i + 1)
+		headers[i].Hash = helper.RandomBlock(uint64(i+1), 1).Header.Hash
+	}
+
+	headers[249] = blk.Header
+
+	resp, err := s.requestBlocks("peer_a", 0, headers)
+	assert.NoError(err)
+
+	assert.Len(s.ranges, 3)
+	assert.Equal(uint64(1), s.ranges[0].from)
+	assert.Equal(uint64(100), s.ranges[0].to)
+	assert.Equal(uint64(250), s.ranges[2].to)
+
+	// The ranges of the peer initiating the sync are sent back as a response
+	assert.Len(resp, 2)
+
+	for _, buf := range resp {
+		topic, err := topics.Extract(&buf)
+		assert.NoError(err)
+		assert.Equal(topics.GetBlockRange, topic)
+
+		msg := &message.GetBlockRange{}
+		assert.NoError(msg.Decode(&buf))
+		assert.Equal("peer_a", s.rangeOf(msg.From).peer)
+	}
+
+	// The range of the other peer is sent point-to-point
+	m := <-sent
+	assert.Equal("peer_b", m.Metadata().Source)
+	assert.Equal("peer_b", s.rangeOf(101).peer)
+
+	// An unresponsive peer has its range requested from another peer
+	assert.NoError(s.retryRanges("peer_b", 0))
+	assert.Equal("peer_a", s.rangeOf(101).peer)
+	assert.Equal(1, s.rangeOf(101).retries)
+
+	// The retried range is requested with a locator, which older peers answer
+	m = <-sent
+	buf := m.Payload().(message.SafeBuffer).Buffer
+	topic, err := topics.Extract(&buf)
+	assert.NoError(err)
+	assert.Equal(topics.GetBlocks, topic)
+
+	getBlocks := &message.GetBlocks{}
+	assert.NoError(getBlocks.Decode(&buf))
+	assert.Equal(headers[99].Hash, getBlocks.Locators[0])
+
+	// Until the retries are exceeded
+	for i := 1; i < maxSyncRetries; i++ {
+		assert.NoError(s.retryRanges("peer_a", 0))
+	}
+
+	assert.Equal(errSyncRetriesExceeded, s.retryRanges("peer_a", 0))
+}
+
+// TestReportInvalidBlock ensures only invalid blocks are held against the
+// peer, not failures of the node to accept them.
+func TestReportInvalidBlock(t *testing.T) {
+	assert := assert.New(t)
+	s, _, eb := setupSynchronizerTest()
+
+	misbehaviors := make(chan message.Message, 2)
+	eb.Subscribe(topics.Misbehavior, eventbus.NewChanListener(misbehaviors))
+
+	m := s.chain.(*mockChain)
+	blk := helper.RandomBlock(1, 1)
+
+	m.acceptErr = errors.New("rusk unavailable")
+	_, err := s.processBlock("10.0.0.1:7000", 0, *blk, nil)
+	assert.Error(err)
+	assert.Empty(misbehaviors)
+
+	// A state hash mismatch may result from a local Rusk inconsistency
+	m.acceptErr = errInvalidStateHash
+	_, err = s.processBlock("10.0.0.1:7000", 0, *blk, nil)
+	assert.Error(err)
+	assert.Empty(misbehaviors)
+
+	m.acceptErr = invalidBlockError{errors.New("invalid certificate")}
+	_, err = s.processBlock("10.0.0.1:7000", 0, *blk, nil)
+	assert.Error(err)
+
+	msg := <-misbehaviors
+	assert.Equal(scoring.InvalidBlock, msg.Payload().(scoring.Misbehavior).Score)
+}
+
+func setupSynchronizerTest() (*synchronizer, chan consensus.Results, *eventbus.EventBus) {
 	c := make(chan consensus.Results, 1)
 	m := &mockChain{tipHeight: 0, catchBlockChan: c}
 	_, db := lite.CreateDBConnection()
@@ -64,12 +175,14 @@ func setupSynchronizerTest() (*synchronizer, chan consensus.Results) {
 		panic(err)
 	}
 
-	return newSynchronizer(db, m), c
+	eb := eventbus.New()
+	return newSynchronizer(db, m, eb), c, eb
 }
 
 type mockChain struct {
 	tipHeight      uint64
 	catchBlockChan chan consensus.Results
+	acceptErr      error
 }
 
 func (m *mockChain) CurrentHeight() uint64 {
@@ -77,6 +190,10 @@ func (m *mockChain) CurrentHeight() uint64 {
 }
 
 func (m *mockChain) TryNextConsecutiveBlockInSync(blk block.Block, _ *message.Metadata) error {
+	if m.acceptErr != nil {
+		return m.acceptErr
+	}
+
 	m.catchBlockChan <- consensus.Results{Blk: blk, Err: nil}
 	return nil
 }
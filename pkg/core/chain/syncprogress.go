@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/syncprogress"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// syncProgressInterval is the min interval between two sync progress
+	// updates, unless the synchronizer state changes.
+	syncProgressInterval = time.Second
+	// syncRateWeight is the weight of the last measure in the moving average
+	// of the sync speed.
+	syncRateWeight = 0.3
+)
+
+// syncMonitor measures the sync speed, and throttles the sync progress
+// updates.
+type syncMonitor struct {
+	height uint64
+	time   time.Time
+	// rate is the moving average of the accepted blocks per second.
+	rate float64
+
+	published time.Time
+	state     string
+}
+
+// measure updates the sync speed with the chain height at a given time. The
+// speed is measured over intervals of at least a second.
+func (m *syncMonitor) measure(height uint64, now time.Time) {
+	if m.time.IsZero() || height < m.height {
+		m.height, m.time = height, now
+		return
+	}
+
+	elapsed := now.Sub(m.time).Seconds()
+	if elapsed < 1 {
+		return
+	}
+
+	rate := float64(height-m.height) / elapsed
+	m.rate = syncRateWeight*rate + (1-syncRateWeight)*m.rate
+	m.height, m.time = height, now
+}
+
+// syncProgress returns the current sync progress.
+func (c *Chain) syncProgress() syncprogress.Progress {
+	p := syncprogress.Progress{
+		CurrentHeight:   c.tip.Header.Height,
+		TargetHeight:    c.highestSeen,
+		BlocksPerSecond: c.monitor.rate,
+		State:           syncprogress.StateInSync,
+	}
+
+	if p.TargetHeight < p.CurrentHeight {
+		p.TargetHeight = p.CurrentHeight
+	}
+
+	if c.synchronizer.syncing {
+		p.State = syncprogress.StateOutSync
+		p.Peer = c.synchronizer.syncPeer
+	}
+
+	if p.BlocksPerSecond > 0 {
+		p.ETA = uint64(float64(p.TargetHeight-p.CurrentHeight) / p.BlocksPerSecond)
+	}
+
+	return p
+}
+
+// publishSyncProgress publishes the sync progress on topics.SyncProgress. It
+// is throttled to an update per syncProgressInterval, unless the synchronizer
+// state changed. The chain lock must be held.
+func (c *Chain) publishSyncProgress() {
+	now := time.Now()
+	c.monitor.measure(c.tip.Header.Height, now)
+
+	p := c.syncProgress()
+	if p.State == c.monitor.state && now.Sub(c.monitor.published) < syncProgressInterval {
+		return
+	}
+
+	c.monitor.state, c.monitor.published = p.State, now
+	c.eventBus.Publish(topics.SyncProgress, message.New(topics.SyncProgress, p))
+}
+
+// WatchSyncProgress streams the sync progress until the client cancels the
+// stream.
+func (c *Chain) WatchSyncProgress(_ *emptypb.Empty, stream syncprogress.SyncProgress_WatchSyncProgressServer) error {
+	progressChan := make(chan message.Message, 100)
+	id := c.eventBus.Subscribe(topics.SyncProgress, eventbus.NewChanListener(progressChan))
+
+	defer c.eventBus.Unsubscribe(topics.SyncProgress, id)
+
+	c.lock.RLock()
+	p := c.syncProgress()
+	c.lock.RUnlock()
+
+	if err := stream.Send(p.ToProto()); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case m := <-progressChan:
+			p := m.Payload().(syncprogress.Progress)
+			if err := stream.Send(p.ToProto()); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-c.ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/syncprogress"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSyncMonitor(t *testing.T) {
+	assert := assert.New(t)
+
+	var m syncMonitor
+
+	now := time.Now()
+	m.measure(100, now)
+
+	// Intervals shorter than a second are not measured
+	m.measure(105, now.Add(500*time.Millisecond))
+	assert.Zero(m.rate)
+
+	m.measure(110, now.Add(2*time.Second))
+	assert.InDelta(syncRateWeight*5, m.rate, 0.0001)
+}
+
+func TestPublishSyncProgress(t *testing.T) {
+	assert := assert.New(t)
+	eb, c := setupChainTest(t, 1)
+
+	progressChan := make(chan message.Message, 10)
+	eb.Subscribe(topics.SyncProgress, eventbus.NewChanListener(progressChan))
+
+	c.lock.Lock()
+	c.highestSeen = c.tip.Header.Height + 100
+	c.monitor.rate = 4
+	c.synchronizer.syncing = true
+	c.synchronizer.syncPeer = "peer_a"
+
+	c.publishSyncProgress()
+	// Updates are throttled unless the state changes
+	c.publishSyncProgress()
+	c.lock.Unlock()
+
+	p := (<-progressChan).Payload().(syncprogress.Progress)
+	assert.Equal(c.tip.Header.Height, p.CurrentHeight)
+	assert.Equal(c.tip.Header.Height+100, p.TargetHeight)
+	assert.Equal(uint64(25), p.ETA)
+	assert.Equal("peer_a", p.Peer)
+	assert.Equal(syncprogress.StateOutSync, p.State)
+	assert.Empty(progressChan)
+
+	// Round trip over protobuf
+	assert.Equal(p, syncprogress.FromProto(p.ToProto()))
+}
@@ -157,11 +157,15 @@ func (bg *generator) GenerateBlock(ctx context.Context, round uint64, seed, prev
 
 	blockGasLimit := config.Get().State.BlockGasLimit
 
+	fetched := txs
+
 	txs, stateHash, err := bg.execute(context.Background(), txs, round, blockGasLimit)
 	if err != nil {
 		return nil, err
 	}
 
+	bg.publishDroppedTxs(fetched, txs)
+
 	timestamp := time.Now().Unix()
 	maxTimestamp := prevBlockTimestamp + config.MaxBlockTime
 
@@ -220,6 +224,35 @@ func (bg *generator) FetchMempoolTxs() ([]transactions.ContractCall, error) {
 	return resp.([]transactions.ContractCall), nil
 }
 
+// publishDroppedTxs notifies the mempool of the txs fetched from it, but
+// dropped by Rusk on executing the state transition.
+func (bg *generator) publishDroppedTxs(fetched, executed []transactions.ContractCall) {
+	included := make(map[string]struct{}, len(executed))
+
+	for _, tx := range executed {
+		if hash, err := tx.CalculateHash(); err == nil {
+			included[string(hash)] = struct{}{}
+		}
+	}
+
+	dropped := message.Inv{}
+
+	for _, tx := range fetched {
+		hash, err := tx.CalculateHash()
+		if err != nil {
+			continue
+		}
+
+		if _, ok := included[string(hash)]; !ok {
+			dropped.AddItem(message.InvTypeMempoolTx, hash)
+		}
+	}
+
+	if len(dropped.InvList) > 0 {
+		bg.EventBus.Publish(topics.DroppedTxs, message.New(topics.DroppedTxs, dropped))
+	}
+}
+
 func (bg *generator) sign(seed []byte) ([]byte, error) {
 	return bls.Sign(bg.Keys.BLSSecretKey, bg.Keys.BLSPubKey, seed)
 }
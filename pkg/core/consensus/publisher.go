@@ -8,11 +8,16 @@ package consensus
 
 import (
 	"bytes"
+	"errors"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/user"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 )
 
+var errMalformedHeader = errors.New("malformed consensus message header")
+
 // Publisher is used to direct consensus messages from the peer.MessageProcessor
 // to the consensus components.
 type Publisher struct {
@@ -24,9 +29,17 @@ func NewPublisher(publisher eventbus.Publisher) *Publisher {
 	return &Publisher{publisher}
 }
 
-// Process incoming consensus messages.
+// Process incoming consensus messages. Messages with a malformed header are
+// discarded, and their sender is reported as misbehaving.
 // Satisfies the peer.ProcessorFunc interface.
 func (p *Publisher) Process(srcPeerID string, msg message.Message) ([]bytes.Buffer, error) {
+	if pkt, ok := msg.Payload().(InternalPacket); ok {
+		if len(pkt.State().PubKeyBLS) != user.BlsKeySize {
+			scoring.Report(p.publisher, srcPeerID, scoring.InvalidConsensusMsg, errMalformedHeader.Error())
+			return nil, errMalformedHeader
+		}
+	}
+
 	p.publisher.Publish(msg.Category(), msg)
 	return nil, nil
 }
@@ -85,7 +85,7 @@ func (p *PermissiveExecutor) Persist(context.Context, []byte) error {
 
 // Revert ...
 func (p *PermissiveExecutor) Revert(ctx context.Context) ([]byte, error) {
-	return nil, nil
+	return make([]byte, 32), nil
 }
 
 // MockProxy mocks a proxy for ease of testing.
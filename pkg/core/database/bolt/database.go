@@ -0,0 +1,178 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package bolt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// dbFileName is the name of the bbolt file created in the storage
+	// directory.
+	dbFileName = "chain.db"
+
+	// openTimeout is the time to wait for the file lock. bbolt would
+	// otherwise block forever if the storage is used by another process.
+	openTimeout = 3 * time.Second
+)
+
+// storage is a bbolt instance shared by all DB opened on the same path with
+// the same mode.
+type storage struct {
+	db   *bbolt.DB
+	refs int
+}
+
+// storageKey identifies a storage opened by the driver. bbolt opens a file
+// either read-write or read-only, hence storages are shared per mode.
+type storageKey struct {
+	path     string
+	readOnly bool
+}
+
+// openStorage creates or opens the bbolt file located in the directory path.
+// All buckets are created on the first read-write opening. A read-only
+// storage must have been initialized already.
+func openStorage(path string, readonly bool) (*storage, error) {
+	if !readonly {
+		if err := os.MkdirAll(path, 0700); err != nil {
+			return nil, errors.New("could not open or create db")
+		}
+	}
+
+	opts := &bbolt.Options{Timeout: openTimeout, ReadOnly: readonly}
+
+	db, err := bbolt.Open(filepath.Join(path, dbFileName), 0600, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	prepare := createBuckets
+	if readonly {
+		prepare = checkBuckets
+	}
+
+	if err := prepare(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &storage{db: db}, nil
+}
+
+// createBuckets creates all buckets that do not exist yet.
+func createBuckets(db *bbolt.DB) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkBuckets ensures all buckets exist.
+func checkBuckets(db *bbolt.DB) error {
+	return db.View(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if tx.Bucket(name) == nil {
+				return fmt.Errorf("bucket %s not found", name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DB on top of underlying storage etcd-io/bbolt.
+type DB struct {
+	storage *storage
+	driver  *driver
+	key     storageKey
+
+	// Read-only mode provided at bolt.DB level. If true, accepts read-only
+	// Transaction.
+	readOnly bool
+
+	// Secondary tx indices enabled.
+	indexNullifiers bool
+	indexContracts  bool
+
+	closeOnce sync.Once
+}
+
+// Begin builds read-only or read-write Transaction.
+//
+// Mind that bbolt allows a single read-write transaction at a time. Also a
+// read-write transaction may need to remap the storage file, in which case it
+// waits for all read-only transactions to complete. Hence, a goroutine must
+// not begin a read-write transaction while holding a read-only one.
+func (db *DB) Begin(writable bool) (database.Transaction, error) {
+	// If the database was opened with DB.readonly flag true, we cannot create
+	// a writable transaction
+	if db.readOnly && writable {
+		return nil, errors.New("database is read-only")
+	}
+
+	tx, err := db.storage.db.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{tx: tx, writable: writable, db: db}, nil
+}
+
+// Update a record within a transaction.
+func (db *DB) Update(fn func(database.Transaction) error) error {
+	t, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+
+	defer t.Close()
+
+	// If an error is returned from the function then rollback (on Close) and
+	// return error.
+	if err = fn(t); err != nil {
+		return err
+	}
+
+	return t.Commit()
+}
+
+// View is the equivalent of a Select SQL statement.
+func (db *DB) View(fn func(database.Transaction) error) error {
+	t, err := db.Begin(false)
+	if err != nil {
+		return err
+	}
+
+	defer t.Close()
+	return fn(t)
+}
+
+// Close releases the underlying storage. The storage is closed only if no
+// other DB instance is using it.
+func (db *DB) Close() error {
+	var err error
+
+	db.closeOnce.Do(func() {
+		err = db.driver.release(db.key, db.storage)
+	})
+
+	return err
+}
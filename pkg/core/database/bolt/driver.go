@@ -0,0 +1,135 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package bolt
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	log "github.com/sirupsen/logrus"
+)
+
+// DriverName is the unique identifier for the bolt driver.
+var DriverName = "bolt_v0.1.0"
+
+// driver keeps track of all storages it has opened. A storage is shared by all
+// DB instances opened on the same path with the same mode and it is closed once
+// the last of them is closed.
+//
+// The driver does not rely on any package-level state. Independent drivers
+// can be created with NewDriver.
+type driver struct {
+	mu       sync.Mutex
+	storages map[storageKey]*storage
+}
+
+// NewDriver returns a bolt driver that is not registered in the database
+// package. It allows multiple independent chain databases in a single
+// process.
+func NewDriver() database.Driver {
+	return &driver{storages: make(map[storageKey]*storage)}
+}
+
+func (d *driver) Open(path string, readonly bool) (database.DB, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := storageKey{path: abs, readOnly: readonly}
+
+	// The file lock held by a read-write storage prevents opening the same
+	// file in read-only mode, and the other way around. A read-write storage
+	// serves read-only DB instances too, as the DB level mode is enforced on
+	// Begin.
+	if _, ok := d.storages[storageKey{path: abs}]; ok {
+		key.readOnly = false
+	} else if _, ok := d.storages[storageKey{path: abs, readOnly: true}]; ok && !readonly {
+		return nil, errors.New("storage is open in read-only mode")
+	}
+
+	s, ok := d.storages[key]
+	if !ok {
+		if s, err = openStorage(abs, key.readOnly); err != nil {
+			return nil, err
+		}
+
+		d.storages[key] = s
+	}
+
+	s.refs++
+
+	conf := cfg.Get().Database
+
+	return &DB{
+		storage:         s,
+		driver:          d,
+		key:             key,
+		readOnly:        readonly,
+		indexNullifiers: conf.IndexNullifiers,
+		indexContracts:  conf.IndexContracts,
+	}, nil
+}
+
+// release decrements the references to a storage. The storage is closed when
+// there are no more references to it.
+func (d *driver) release(key storageKey, s *storage) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// The storage might have been closed already by driver.Close.
+	if d.storages[key] != s {
+		return nil
+	}
+
+	s.refs--
+	if s.refs > 0 {
+		return nil
+	}
+
+	delete(d.storages, key)
+	return s.db.Close()
+}
+
+// Close closes all storages opened by the driver, regardless of their
+// references.
+func (d *driver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.storages) == 0 {
+		return errors.New("invalid storage")
+	}
+
+	var err error
+
+	for key, s := range d.storages {
+		if e := s.db.Close(); e != nil {
+			err = e
+		}
+
+		delete(d.storages, key)
+	}
+
+	return err
+}
+
+func (d *driver) Name() string {
+	return DriverName
+}
+
+func init() {
+	if err := database.Register(NewDriver()); err != nil {
+		log.Panic(err)
+	}
+}
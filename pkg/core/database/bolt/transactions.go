@@ -0,0 +1,762 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	// Each kind of record is stored in its own bucket. Refer to bolt.md for
+	// an overview of the schema.
+
+	// HeadersBucket maps a block hash to the block header.
+	HeadersBucket = []byte("headers")
+	// TxsBucket maps a block hash + txID to the transaction.
+	TxsBucket = []byte("txs")
+	// HeightsBucket maps a block height to the block hash.
+	HeightsBucket = []byte("heights")
+	// TxIDsBucket maps a txID to the hash of the block it belongs to.
+	TxIDsBucket = []byte("txids")
+	// RegistryBucket stores the chain registry.
+	RegistryBucket = []byte("registry")
+	// CandidatesBucket maps a block hash to a candidate block.
+	CandidatesBucket = []byte("candidates")
+	// CandidateRoundsBucket indexes candidate blocks by round + block hash.
+	CandidateRoundsBucket = []byte("candidaterounds")
+	// StateRootsBucket maps a state root to the height of a block.
+	StateRootsBucket = []byte("stateroots")
+	// NullifiersBucket maps a spent nullifier to a txID.
+	NullifiersBucket = []byte("nullifiers")
+	// ContractsBucket maps a contractID + height + tx index to a txID.
+	ContractsBucket = []byte("contracts")
+
+	buckets = [][]byte{
+		HeadersBucket, TxsBucket, HeightsBucket, TxIDsBucket,
+		RegistryBucket, CandidatesBucket, CandidateRoundsBucket,
+		StateRootsBucket, NullifiersBucket, ContractsBucket,
+	}
+
+	// Keys of RegistryBucket.
+	tipKey       = []byte("tip")
+	persistedKey = []byte("persisted")
+	journalKey   = []byte("journal")
+)
+
+// heightKey encodes a height in big endian so that keys of HeightsBucket are
+// sorted by height.
+func heightKey(height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return key
+}
+
+// txKey builds the TxsBucket key of a transaction.
+func txKey(hash, txID []byte) []byte {
+	key := make([]byte, 0, len(hash)+len(txID))
+	return append(append(key, hash...), txID...)
+}
+
+// contractKey builds the ContractsBucket key of a transaction. Keys of the
+// same contract are sorted by height and tx index.
+func contractKey(contractID []byte, height uint64, txIndex uint32) []byte {
+	key := make([]byte, len(contractID)+12)
+
+	n := copy(key, contractID)
+	binary.BigEndian.PutUint64(key[n:], height)
+	binary.BigEndian.PutUint32(key[n+8:], txIndex)
+
+	return key
+}
+
+// candidateRoundKey builds the CandidateRoundsBucket key of a candidate block.
+func candidateRoundKey(round uint64, hash []byte) []byte {
+	return append(heightKey(round), hash...)
+}
+
+type transaction struct {
+	tx       *bbolt.Tx
+	writable bool
+	db       *DB
+}
+
+// get returns a copy of the value of a key. Values returned by bbolt are
+// valid only for the life of the transaction.
+func (t transaction) get(bucket, key []byte) []byte {
+	value := t.tx.Bucket(bucket).Get(key)
+	if value == nil {
+		return nil
+	}
+
+	return append([]byte{}, value...)
+}
+
+func (t transaction) put(bucket, key, value []byte) error {
+	return t.tx.Bucket(bucket).Put(key, value)
+}
+
+func (t transaction) delete(bucket, key []byte) error {
+	return t.tx.Bucket(bucket).Delete(key)
+}
+
+// StoreBlock stores the entire block data into storage. No validations are
+// applied. Storage state changes only when Commit() is called on Transaction
+// completion.
+func (t transaction) StoreBlock(b *block.Block, persisted bool) error {
+	if !t.writable {
+		return errors.New("StoreBlock cannot be called on read-only transaction")
+	}
+
+	if len(b.Header.Hash) != block.HeaderHashSize {
+		return fmt.Errorf("header hash size is %d but it must be %d", len(b.Header.Hash), block.HeaderHashSize)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := message.MarshalHeader(buf, b.Header); err != nil {
+		return err
+	}
+
+	if err := t.put(HeadersBucket, b.Header.Hash, buf.Bytes()); err != nil {
+		return err
+	}
+
+	if uint64(len(b.Txs)) > math.MaxUint32 {
+		return errors.New("too many transactions")
+	}
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		if len(txID) == 0 {
+			return fmt.Errorf("empty chain tx id")
+		}
+
+		entry, err := utils.EncodeBlockTx(tx, uint32(i))
+		if err != nil {
+			return err
+		}
+
+		if err := t.put(TxsBucket, txKey(b.Header.Hash, txID), entry); err != nil {
+			return err
+		}
+
+		if err := t.put(TxIDsBucket, txID, b.Header.Hash); err != nil {
+			return err
+		}
+
+		if err := t.indexTx(tx, txID, b.Header.Height, uint32(i), false); err != nil {
+			return err
+		}
+	}
+
+	height := heightKey(b.Header.Height)
+
+	if err := t.put(HeightsBucket, height, b.Header.Hash); err != nil {
+		return err
+	}
+
+	if len(b.Header.StateHash) > 0 {
+		if err := t.put(StateRootsBucket, b.Header.StateHash, height); err != nil {
+			return err
+		}
+	}
+
+	if err := t.put(RegistryBucket, tipKey, b.Header.Hash); err != nil {
+		return err
+	}
+
+	if persisted {
+		return t.put(RegistryBucket, persistedKey, b.Header.Hash)
+	}
+
+	return nil
+}
+
+// DeleteBlock deletes all records associated with a specified block.
+func (t transaction) DeleteBlock(b *block.Block) error {
+	if !t.writable {
+		return errors.New("DeleteBlock cannot be called on read-only transaction")
+	}
+
+	if err := t.delete(HeadersBucket, b.Header.Hash); err != nil {
+		return err
+	}
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		if err := t.delete(TxsBucket, txKey(b.Header.Hash, txID)); err != nil {
+			return err
+		}
+
+		if err := t.delete(TxIDsBucket, txID); err != nil {
+			return err
+		}
+
+		if err := t.indexTx(tx, txID, b.Header.Height, uint32(i), true); err != nil {
+			return err
+		}
+	}
+
+	height := heightKey(b.Header.Height)
+
+	if err := t.delete(HeightsBucket, height); err != nil {
+		return err
+	}
+
+	// A state root might be shared by more than one block. Delete the entry
+	// only if it still points at this block.
+	if len(b.Header.StateHash) > 0 && bytes.Equal(t.get(StateRootsBucket, b.Header.StateHash), height) {
+		return t.delete(StateRootsBucket, b.Header.StateHash)
+	}
+
+	return nil
+}
+
+// indexTx puts or deletes the records of the secondary tx indices, if enabled.
+func (t transaction) indexTx(tx transactions.ContractCall, txID []byte, height uint64, txIndex uint32, remove bool) error {
+	if !t.db.indexNullifiers && !t.db.indexContracts {
+		return nil
+	}
+
+	decoded, err := tx.Decode()
+	if err != nil {
+		// A tx with undecodable payload cannot be indexed but it is still a
+		// valid chain tx.
+		return nil
+	}
+
+	modify := func(bucket, key []byte) error {
+		if remove {
+			return t.delete(bucket, key)
+		}
+
+		return t.put(bucket, key, txID)
+	}
+
+	if t.db.indexNullifiers {
+		for _, nullifier := range decoded.Nullifiers {
+			if err := modify(NullifiersBucket, nullifier); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.db.indexContracts && decoded.Call != nil {
+		return modify(ContractsBucket, contractKey(decoded.Call.ContractID, height, txIndex))
+	}
+
+	return nil
+}
+
+// Commit writes all changes of a read-write transaction to the storage.
+func (t *transaction) Commit() error {
+	if !t.writable {
+		return errors.New("read-only transaction cannot commit changes")
+	}
+
+	if t.tx.DB() == nil {
+		return errors.New("already closed transaction cannot commit changes")
+	}
+
+	return t.tx.Commit()
+}
+
+// Rollback discards all changes of the transaction.
+func (t transaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Close rolls back the transaction, if not committed yet. It must be called
+// explicitly when a transaction is run in a unmanaged way.
+func (t *transaction) Close() {
+	if t.tx.DB() != nil {
+		_ = t.tx.Rollback()
+	}
+}
+
+func (t transaction) FetchBlockExists(hash []byte) (bool, error) {
+	if t.tx.Bucket(HeadersBucket).Get(hash) == nil {
+		return false, database.ErrBlockNotFound
+	}
+
+	return true, nil
+}
+
+func (t transaction) FetchBlockHeader(hash []byte) (*block.Header, error) {
+	value := t.tx.Bucket(HeadersBucket).Get(hash)
+	if value == nil {
+		return nil, database.ErrBlockNotFound
+	}
+
+	header := block.NewHeader()
+	if err := message.UnmarshalHeader(bytes.NewBuffer(value), header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+func (t transaction) FetchBlockTxs(hash []byte) ([]transactions.ContractCall, error) {
+	tempTxs := make(map[uint32]transactions.ContractCall)
+
+	// Read all the transactions that belong to a single block
+	c := t.tx.Bucket(TxsBucket).Cursor()
+
+	for k, v := c.Seek(hash); k != nil && bytes.HasPrefix(k, hash); k, v = c.Next() {
+		tx, txIndex, err := utils.DecodeBlockTx(v, database.AnyTxType)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := tempTxs[txIndex]; ok {
+			return nil, errors.New("duplicated tx index")
+		}
+
+		tempTxs[txIndex] = tx
+	}
+
+	// Reorder Tx slice as per retrieved indexes
+	resultTxs := make([]transactions.ContractCall, len(tempTxs))
+	for k, v := range tempTxs {
+		resultTxs[k] = v
+	}
+
+	return resultTxs, nil
+}
+
+func (t transaction) FetchBlockHashByHeight(height uint64) ([]byte, error) {
+	hash := t.get(HeightsBucket, heightKey(height))
+	if hash == nil {
+		return nil, database.ErrBlockNotFound
+	}
+
+	return hash, nil
+}
+
+// IterateHeaders iterates the headers of a height range with a cursor over
+// HeightsBucket.
+func (t transaction) IterateHeaders(from, to uint64, fn func(*block.Header) (bool, error)) error {
+	if from > to {
+		return nil
+	}
+
+	c := t.tx.Bucket(HeightsBucket).Cursor()
+	height := from
+
+	for k, hash := c.Seek(heightKey(from)); k != nil; k, hash = c.Next() {
+		// Stop at the first missing height
+		if binary.BigEndian.Uint64(k) != height {
+			return nil
+		}
+
+		header, err := t.FetchBlockHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		next, err := fn(header)
+		if err != nil || !next || height == to {
+			return err
+		}
+
+		height++
+	}
+
+	return nil
+}
+
+// IterateTxIDs iterates TxIDsBucket with a cursor.
+func (t transaction) IterateTxIDs(fn func(txID, blockHash []byte) (bool, error)) error {
+	c := t.tx.Bucket(TxIDsBucket).Cursor()
+
+	for txID, hash := c.First(); txID != nil; txID, hash = c.Next() {
+		next, err := fn(txID, hash)
+		if err != nil || !next {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall, uint32, []byte, error) {
+	txIndex := uint32(math.MaxUint32)
+
+	// Fetch the block header hash that this Tx belongs to
+	hash := t.get(TxIDsBucket, txID)
+	if hash == nil {
+		return nil, txIndex, nil, database.ErrTxNotFound
+	}
+
+	value := t.tx.Bucket(TxsBucket).Get(txKey(hash, txID))
+	if value == nil {
+		return nil, txIndex, nil, errors.New("block tx is available but fetching it fails")
+	}
+
+	tx, idx, err := utils.DecodeBlockTx(value, database.AnyTxType)
+	if err != nil {
+		return nil, idx, hash, err
+	}
+
+	return tx, idx, hash, nil
+}
+
+func (t transaction) FetchTxByNullifier(nullifier []byte) (transactions.ContractCall, uint32, []byte, error) {
+	if !t.db.indexNullifiers {
+		return nil, math.MaxUint32, nil, database.ErrIndexDisabled
+	}
+
+	txID := t.get(NullifiersBucket, nullifier)
+	if txID == nil {
+		return nil, math.MaxUint32, nil, database.ErrTxNotFound
+	}
+
+	return t.FetchBlockTxByHash(txID)
+}
+
+func (t transaction) FetchTxsByContract(contractID []byte, limit int) ([][]byte, error) {
+	if !t.db.indexContracts {
+		return nil, database.ErrIndexDisabled
+	}
+
+	txIDs := make([][]byte, 0)
+	c := t.tx.Bucket(ContractsBucket).Cursor()
+
+	// Latest txs first. Position the cursor at the last key of the contract.
+	last := contractKey(contractID, math.MaxUint64, math.MaxUint32)
+
+	k, v := c.Seek(last)
+	if k == nil {
+		k, v = c.Last()
+	} else if !bytes.Equal(k, last) {
+		k, v = c.Prev()
+	}
+
+	for ; k != nil && bytes.HasPrefix(k, contractID) && len(txIDs) < limit; k, v = c.Prev() {
+		txIDs = append(txIDs, append([]byte{}, v...))
+	}
+
+	return txIDs, nil
+}
+
+func (t transaction) FetchBlock(hash []byte) (*block.Block, error) {
+	header, err := t.FetchBlockHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := t.FetchBlockTxs(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &block.Block{
+		Header: header,
+		Txs:    txs,
+	}, nil
+}
+
+func (t transaction) FetchRegistry() (*database.Registry, error) {
+	tipHash := t.get(RegistryBucket, tipKey)
+	if len(tipHash) == 0 {
+		return nil, database.ErrStateNotFound
+	}
+
+	persistedHash := t.get(RegistryBucket, persistedKey)
+	if len(persistedHash) == 0 {
+		return nil, database.ErrStateNotFound
+	}
+
+	return &database.Registry{
+		TipHash:       tipHash,
+		PersistedHash: persistedHash,
+	}, nil
+}
+
+func (t transaction) FetchCurrentHeight() (uint64, error) {
+	state, err := t.FetchRegistry()
+	if err != nil {
+		return 0, err
+	}
+
+	header, err := t.FetchBlockHeader(state.TipHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Height, nil
+}
+
+// FetchBlockHeightSince uses binary search to find a block height.
+// NB: Duplicates FetchBlockHeightSince heavy driver.
+func (t transaction) FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error) {
+	tip, err := t.FetchCurrentHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	n := uint64(math.Min(float64(tip), float64(offset)))
+
+	pos, err := utils.Search(n, func(pos uint64) (bool, error) {
+		height := tip - n + pos
+
+		hash, heightErr := t.FetchBlockHashByHeight(height)
+		if heightErr != nil {
+			return false, heightErr
+		}
+
+		header, blockHdrErr := t.FetchBlockHeader(hash)
+		if blockHdrErr != nil {
+			return false, blockHdrErr
+		}
+
+		return header.Timestamp >= sinceUnixTime, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return tip - n + pos, nil
+}
+
+func (t transaction) StoreCandidateMessage(cm block.Block) error {
+	if !t.writable {
+		return errors.New("StoreCandidateMessage cannot be called on read-only transaction")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, &cm); err != nil {
+		return err
+	}
+
+	if err := t.put(CandidatesBucket, cm.Header.Hash, buf.Bytes()); err != nil {
+		return err
+	}
+
+	return t.put(CandidateRoundsBucket, candidateRoundKey(cm.Header.Height, cm.Header.Hash), []byte{})
+}
+
+func (t transaction) FetchCandidateMessage(hash []byte) (block.Block, error) {
+	value := t.tx.Bucket(CandidatesBucket).Get(hash)
+	if value == nil {
+		return block.Block{}, database.ErrBlockNotFound
+	}
+
+	cm := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(value), cm); err != nil {
+		return block.Block{}, err
+	}
+
+	return *cm, nil
+}
+
+// FetchCandidateMessagesByRound returns all candidate blocks of a round.
+func (t transaction) FetchCandidateMessagesByRound(round uint64) ([]block.Block, error) {
+	prefix := heightKey(round)
+	cms := make([]block.Block, 0)
+
+	c := t.tx.Bucket(CandidateRoundsBucket).Cursor()
+
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		cm, err := t.FetchCandidateMessage(k[len(prefix):])
+		if err != nil {
+			return nil, err
+		}
+
+		cms = append(cms, cm)
+	}
+
+	return cms, nil
+}
+
+// DeleteCandidateMessages deletes all candidate blocks of the rounds up to
+// maxRound (included).
+func (t transaction) DeleteCandidateMessages(maxRound uint64) error {
+	if !t.writable {
+		return errors.New("DeleteCandidateMessages cannot be called on read-only transaction")
+	}
+
+	// Keys are collected first as deleting while iterating a cursor may skip
+	// records.
+	keys := make([][]byte, 0)
+
+	c := t.tx.Bucket(CandidateRoundsBucket).Cursor()
+
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		if binary.BigEndian.Uint64(k) > maxRound {
+			break
+		}
+
+		keys = append(keys, append([]byte{}, k...))
+	}
+
+	for _, k := range keys {
+		if err := t.delete(CandidatesBucket, k[8:]); err != nil {
+			return err
+		}
+
+		if err := t.delete(CandidateRoundsBucket, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchBlockByStateRoot finds a block that is linked to a specified state_root.
+// It looks up StateRootsBucket first. If the index cannot resolve it, it loops
+// through all blocks in reverse order.
+// NB: Duplicates FetchBlockByStateRoot heavy driver.
+func (t transaction) FetchBlockByStateRoot(fromHeight uint64, stateRoot []byte) (*block.Block, error) {
+	if b, err := t.fetchBlockByStateRootIndex(fromHeight, stateRoot); err == nil {
+		return b, nil
+	}
+
+	i := fromHeight
+
+	for {
+		hash, err := t.FetchBlockHashByHeight(i)
+		if err != nil {
+			return nil, err
+		}
+
+		header, err := t.FetchBlockHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(header.StateHash, stateRoot) {
+			txs, err := t.FetchBlockTxs(hash)
+			if err != nil {
+				return nil, err
+			}
+
+			return &block.Block{Header: header, Txs: txs}, nil
+		}
+
+		if i == 0 {
+			// If this point is reached, all blocks including genesis does
+			// not know this state_root.
+			return nil, database.ErrStateHashNotFound
+		}
+
+		i--
+	}
+}
+
+func (t transaction) fetchBlockByStateRootIndex(fromHeight uint64, stateRoot []byte) (*block.Block, error) {
+	value := t.tx.Bucket(StateRootsBucket).Get(stateRoot)
+	if len(value) != 8 {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	height := binary.BigEndian.Uint64(value)
+	if height > fromHeight {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	hash, err := t.FetchBlockHashByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := t.FetchBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sanity check in case of a stale index entry
+	if !bytes.Equal(b.Header.StateHash, stateRoot) {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	return b, nil
+}
+
+func (t transaction) ClearCandidateMessages() error {
+	return t.clearBuckets(CandidatesBucket, CandidateRoundsBucket)
+}
+
+// StoreJournal records the block being accepted.
+func (t transaction) StoreJournal(b *block.Block) error {
+	if !t.writable {
+		return errors.New("StoreJournal cannot be called on read-only transaction")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, b); err != nil {
+		return err
+	}
+
+	return t.put(RegistryBucket, journalKey, buf.Bytes())
+}
+
+// FetchJournal returns the block being accepted, if any.
+func (t transaction) FetchJournal() (*block.Block, error) {
+	value := t.tx.Bucket(RegistryBucket).Get(journalKey)
+	if value == nil {
+		return nil, database.ErrJournalEmpty
+	}
+
+	b := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(value), b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// DeleteJournal deletes the journal.
+func (t transaction) DeleteJournal() error {
+	if !t.writable {
+		return errors.New("DeleteJournal cannot be called on read-only transaction")
+	}
+
+	return t.delete(RegistryBucket, journalKey)
+}
+
+// ClearDatabase will wipe all of the data currently in the database.
+func (t transaction) ClearDatabase() error {
+	return t.clearBuckets(buckets...)
+}
+
+// clearBuckets drops and re-creates the specified buckets.
+func (t transaction) clearBuckets(names ...[]byte) error {
+	if !t.writable {
+		return errors.New("read-only transaction cannot clear buckets")
+	}
+
+	for _, name := range names {
+		if err := t.tx.DeleteBucket(name); err != nil {
+			return err
+		}
+
+		if _, err := t.tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
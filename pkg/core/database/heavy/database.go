@@ -15,79 +15,84 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/errors"
 )
 
-var (
-	// See openStorage for detailed explanation.
-	_storage   *leveldb.DB
-	_storageMu sync.Mutex
-)
+// storage is a leveldb instance shared by all DB instances opened on the same
+// path.
+type storage struct {
+	db   *leveldb.DB
+	refs int
+
+	// versioned is true once the storage is marked with SchemaVersion.
+	versioned bool
+}
+
+// storageRef is a reference to a storage held by a DB instance. It is shared
+// by all copies of a DB value so that the reference is released only once.
+type storageRef struct {
+	drvr *driver
+	path string
+	s    *storage
+	once sync.Once
+}
+
+func (r *storageRef) release() error {
+	var err error
+
+	r.once.Do(func() {
+		err = r.drvr.release(r.path, r.s)
+	})
+
+	return err
+}
 
 // DB on top of underlying storage syndtr/goleveldb/leveldb.
 type DB struct {
-	// an alias to the global storage var.
+	// storage shared with other DB instances opened on the same path.
 	storage *leveldb.DB
+	ref     *storageRef
 
 	// Read-only mode provided at heavy.DB level. If true, accepts read-only Transaction.
 	readOnly bool
+
+	// Number of blocks behind the persisted block to keep transactions payload
+	// of. Pruning mode is disabled if 0.
+	pruneDepth uint64
+
+	// Secondary tx indices enabled.
+	indexNullifiers bool
+	indexContracts  bool
 }
 
-// openStorage is a wrapper around leveldb.OpenFile to provide singleton
-// leveldb.DB instance.
+// openStorage is a wrapper around leveldb.OpenFile.
 //
 // leveldb.OpenFile returns a new filesystem-backed storage implementation with
 // the given path. This also acquire a file lock, so any subsequent attempt to
-// open the same path will fail.
+// open the same path will fail. That's why the driver shares a storage
+// between all DB instances opened on the same path.
 //
 // Even opening with leveldb.Options{ ReadOnly: true} an err EAGAIN is returned.
 func openStorage(path string) (*leveldb.DB, error) {
-	_storageMu.Lock()
-	defer _storageMu.Unlock()
-
-	var err error
-
-	if _storage == nil {
-		var s *leveldb.DB
-		s, err = leveldb.OpenFile(path, nil)
-
-		// Try to recover if corrupted.
-		if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
-			s, err = leveldb.RecoverFile(path, nil)
-		}
+	s, err := leveldb.OpenFile(path, nil)
 
-		if _, accessdenied := err.(*os.PathError); accessdenied {
-			err = errors.New("could not open or create db")
-		}
-
-		if s != nil && err == nil {
-			_storage = s
-		}
+	// Try to recover if corrupted.
+	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
+		s, err = leveldb.RecoverFile(path, nil)
 	}
-	return _storage, err
-}
-
-// closeStorage should safely close the underlying storage.
-func closeStorage() error {
-	_storageMu.Lock()
-	defer _storageMu.Unlock()
 
-	if _storage != nil {
-		err := _storage.Close()
-		_storage = nil
-		return err
+	if _, accessdenied := err.(*os.PathError); accessdenied {
+		err = errors.New("could not open or create db")
 	}
 
-	return errors.New("invalid storage")
+	return s, err
 }
 
 // NewDatabase create or open backend storage (goleveldb) located at the
 // specified path. Readonly option is pseudo read-only mode implemented by
 // heavy.Database. Not to be confused with read-only goleveldb mode.
+//
+// The storage is owned by the returned DB and it is closed on DB.Close. Use a
+// Driver to share the storage between multiple DB instances.
 func NewDatabase(path string, readonly bool) (database.DB, error) {
-	storage, err := openStorage(path)
-	if err != nil {
-		return nil, err
-	}
-
-	return DB{storage, readonly}, nil
+	return NewDriver().Open(path, readonly)
 }
 
 // Begin builds read-only or read-write Transaction.
@@ -179,13 +184,10 @@ func (db DB) isOpen() bool {
 	return db.storage != nil
 }
 
-// Close does not close the underlying storage as we need to reuse it within
-// another DB instances. Note that we rely on the Finalizer that goleveldb is
-// setting at the point of leveldb.DB.openDB to call leveldb.DB.Close() when
-// the process is terminating.
+// Close releases the underlying storage. The storage is closed only if no
+// other DB instance is using it.
 func (db DB) Close() error {
-	db.storage = nil
-	return nil
+	return db.ref.release()
 }
 
 // GetSnapshot returns current storage snapshot. To be used only by
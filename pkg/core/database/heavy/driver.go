@@ -7,6 +7,10 @@
 package heavy
 
 import (
+	"errors"
+	"path/filepath"
+	"sync"
+
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	log "github.com/sirupsen/logrus"
@@ -15,14 +19,111 @@ import (
 // DriverName is the unique identifier for the heavy driver.
 var DriverName = "heavy_v0.1.0"
 
-type driver struct{}
+// driver keeps track of all storages it has opened. A storage is
+// reference-counted per path and it is closed once the last DB instance using
+// it is closed.
+type driver struct {
+	mu       sync.Mutex
+	storages map[string]*storage
+}
+
+// NewDriver returns a heavy driver that is not registered in the database
+// package. Storages opened by it are not shared with any other driver.
+func NewDriver() database.Driver {
+	return &driver{storages: make(map[string]*storage)}
+}
 
 func (d *driver) Open(path string, readonly bool) (database.DB, error) {
-	return NewDatabase(path, readonly)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := d.storages[key]
+	if !ok {
+		ldb, err := openStorage(key)
+		if err != nil {
+			return nil, err
+		}
+
+		versioned, err := checkSchema(ldb)
+		if err != nil {
+			_ = ldb.Close()
+			return nil, err
+		}
+
+		s = &storage{db: ldb, versioned: versioned}
+		d.storages[key] = s
+	}
+
+	// A new storage is marked with the schema version by the first writable
+	// instance opened on it.
+	if !s.versioned && !readonly {
+		if err := writeSchemaVersion(s.db, SchemaVersion); err != nil {
+			return nil, err
+		}
+
+		s.versioned = true
+	}
+
+	s.refs++
+
+	conf := cfg.Get().Database
+
+	return DB{
+		storage:         s.db,
+		ref:             &storageRef{drvr: d, path: key, s: s},
+		readOnly:        readonly,
+		pruneDepth:      conf.PruneDepth,
+		indexNullifiers: conf.IndexNullifiers,
+		indexContracts:  conf.IndexContracts,
+	}, nil
+}
+
+// release decrements the references to a storage. The storage is closed when
+// there are no more references to it.
+func (d *driver) release(path string, s *storage) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// The storage might have been closed already by driver.Close.
+	if d.storages[path] != s {
+		return nil
+	}
+
+	s.refs--
+	if s.refs > 0 {
+		return nil
+	}
+
+	delete(d.storages, path)
+	return s.db.Close()
 }
 
+// Close closes all storages opened by the driver, regardless of their
+// references.
 func (d *driver) Close() error {
-	return closeStorage()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.storages) == 0 {
+		return errors.New("invalid storage")
+	}
+
+	var err error
+
+	for path, s := range d.storages {
+		if e := s.db.Close(); e != nil {
+			err = e
+		}
+
+		delete(d.storages, path)
+	}
+
+	return err
 }
 
 func (d *driver) Name() string {
@@ -30,9 +131,7 @@ func (d *driver) Name() string {
 }
 
 func init() {
-	d := driver{}
-
-	err := database.Register(&d)
+	err := database.Register(NewDriver())
 	if err != nil {
 		log.Panic(err)
 	}
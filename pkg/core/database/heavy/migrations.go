@@ -0,0 +1,250 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/utils"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	log "github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// SchemaVersion is the version of the storage schema (key layout and records
+// encoding) implemented by this package. Any incompatible change must
+// increase it and register a migration from the previous version.
+//
+// Version 0 denotes a storage created prior to schema versioning.
+const SchemaVersion uint32 = 2
+
+// migrationBatchSize is the number of records written at once by a migration.
+const migrationBatchSize = 1000
+
+var errEmptyStorage = errors.New("empty storage")
+
+// migration upgrades a storage from a schema version to the next one. It must
+// be idempotent as it is run again if interrupted.
+type migration struct {
+	description string
+	run         func(s *leveldb.DB) error
+}
+
+// migrations maps a schema version to the migration upgrading from it.
+var migrations = make(map[uint32]migration)
+
+// registerMigration registers a migration from the schema version from to
+// from + 1.
+func registerMigration(from uint32, description string, run func(s *leveldb.DB) error) {
+	if _, ok := migrations[from]; ok {
+		log.WithField("from", from).Panic("migration already registered")
+	}
+
+	migrations[from] = migration{description, run}
+}
+
+func init() {
+	registerMigration(0, "index headers by height and blocks by state root", migrateHeightAndStateRootIndices)
+	registerMigration(1, "index candidate blocks by round", migrateCandidateRoundIndex)
+}
+
+// readSchemaVersion returns the schema version of a storage. It returns
+// errEmptyStorage if the storage has no records at all.
+func readSchemaVersion(s *leveldb.DB) (uint32, error) {
+	value, err := s.Get(VersionPrefix, nil)
+	if err == leveldb.ErrNotFound {
+		iter := s.NewIterator(nil, nil)
+		defer iter.Release()
+
+		if !iter.First() {
+			return 0, errEmptyStorage
+		}
+
+		// Storage created prior to schema versioning
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	var version uint32
+	if err := utils.ReadUint32(bytes.NewReader(value), &version); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func writeSchemaVersion(s *leveldb.DB, version uint32) error {
+	buf := new(bytes.Buffer)
+	if err := utils.WriteUint32(buf, version); err != nil {
+		return err
+	}
+
+	return s.Put(VersionPrefix, buf.Bytes(), writeOptions)
+}
+
+// checkSchema ensures the storage schema is the one implemented by this
+// package. It never writes to the storage and it reports whether the storage
+// is already marked with the schema version, which is not the case for a new
+// storage.
+func checkSchema(s *leveldb.DB) (bool, error) {
+	version, err := readSchemaVersion(s)
+	if err == errEmptyStorage {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if version != SchemaVersion {
+		return false, schemaVersionError(version)
+	}
+
+	return true, nil
+}
+
+func schemaVersionError(version uint32) error {
+	if version > SchemaVersion {
+		return fmt.Errorf("%w: storage version %d is newer than supported version %d", database.ErrSchemaVersion, version, SchemaVersion)
+	}
+
+	return fmt.Errorf("%w: storage version %d is older than supported version %d, run `dusk migrate`", database.ErrSchemaVersion, version, SchemaVersion)
+}
+
+// Migrate upgrades the storage located at path to SchemaVersion by running all
+// registered migrations in order. The storage must not be in use.
+func Migrate(path string) error {
+	s, err := openStorage(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = s.Close()
+	}()
+
+	version, err := readSchemaVersion(s)
+	if err == errEmptyStorage {
+		return writeSchemaVersion(s, SchemaVersion)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if version > SchemaVersion {
+		return schemaVersionError(version)
+	}
+
+	for ; version < SchemaVersion; version++ {
+		m, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration registered from version %d", version)
+		}
+
+		log.WithField("from", version).WithField("to", version+1).
+			Info("migrate: " + m.description)
+
+		if err := m.run(s); err != nil {
+			return fmt.Errorf("migration from version %d: %w", version, err)
+		}
+
+		if err := writeSchemaVersion(s, version+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateHeightAndStateRootIndices stores HeaderHeightPrefix and
+// StateRootPrefix records of all blocks.
+func migrateHeightAndStateRootIndices(s *leveldb.DB) error {
+	iter := s.NewIterator(util.BytesPrefix(HeightPrefix), nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+
+	for iter.Next() {
+		var height uint64
+		if err := utils.ReadUint64(bytes.NewReader(iter.Key()[len(HeightPrefix):]), &height); err != nil {
+			return err
+		}
+
+		hash := iter.Value()
+
+		value, err := s.Get(append(HeaderPrefix, hash...), nil)
+		if err != nil {
+			return err
+		}
+
+		header := block.NewHeader()
+		if err := message.UnmarshalHeader(bytes.NewBuffer(value), header); err != nil {
+			return err
+		}
+
+		batch.Put(headerHeightKey(height), hash)
+
+		if len(header.StateHash) > 0 {
+			batch.Put(append(StateRootPrefix, header.StateHash...), iter.Key()[len(HeightPrefix):])
+		}
+
+		if batch.Len() >= migrationBatchSize {
+			if err := s.Write(batch, writeOptions); err != nil {
+				return err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return s.Write(batch, writeOptions)
+}
+
+// migrateCandidateRoundIndex stores CandidateRoundPrefix records of all
+// candidate blocks.
+func migrateCandidateRoundIndex(s *leveldb.DB) error {
+	iter := s.NewIterator(util.BytesPrefix(CandidatePrefix), nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+
+	for iter.Next() {
+		header := block.NewHeader()
+		if err := message.UnmarshalHeader(bytes.NewBuffer(iter.Value()), header); err != nil {
+			return err
+		}
+
+		batch.Put(candidateRoundKey(header.Height, iter.Key()[len(CandidatePrefix):]), []byte{})
+
+		if batch.Len() >= migrationBatchSize {
+			if err := s.Write(batch, writeOptions); err != nil {
+				return err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return s.Write(batch, writeOptions)
+}
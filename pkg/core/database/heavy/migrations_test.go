@@ -0,0 +1,210 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	assert "github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// indexPrefixes are the prefixes of records missing in a storage created prior
+// to schema versioning.
+var indexPrefixes = [][]byte{HeaderHeightPrefix, StateRootPrefix, CandidateRoundPrefix, VersionPrefix}
+
+func tempStorage(t *testing.T) string {
+	dir, err := ioutil.TempDir(os.TempDir(), "heavy_migrations_")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+// createBaselineStorage stores blocks and candidates at path and then deletes
+// all records introduced by the migrations, so that the storage has the
+// layout of a storage created prior to schema versioning. It returns the
+// deleted records.
+func createBaselineStorage(t *testing.T, path string) map[string][]byte {
+	db, err := NewDatabase(path, false)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Update(func(tx database.Transaction) error {
+		for h := uint64(0); h < 5; h++ {
+			b := helper.RandomBlock(h, 1)
+
+			b.Header.StateHash = make([]byte, 32)
+			if _, err := rand.Read(b.Header.StateHash); err != nil {
+				return err
+			}
+
+			if err := tx.StoreBlock(b, false); err != nil {
+				return err
+			}
+
+			if err := tx.StoreCandidateMessage(*helper.RandomBlock(h+1, 1)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	assert.NoError(t, db.Close())
+
+	s, err := openStorage(path)
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = s.Close()
+	}()
+
+	deleted := make(map[string][]byte)
+	batch := new(leveldb.Batch)
+
+	for _, prefix := range indexPrefixes {
+		iter := s.NewIterator(util.BytesPrefix(prefix), nil)
+
+		for iter.Next() {
+			deleted[string(iter.Key())] = append([]byte{}, iter.Value()...)
+			batch.Delete(iter.Key())
+		}
+
+		iter.Release()
+		assert.NoError(t, iter.Error())
+	}
+
+	assert.NoError(t, s.Write(batch, nil))
+	return deleted
+}
+
+func hasVersion(t *testing.T, path string) bool {
+	s, err := openStorage(path)
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = s.Close()
+	}()
+
+	ok, err := s.Has(VersionPrefix, nil)
+	assert.NoError(t, err)
+
+	return ok
+}
+
+// TestMigrate ensures a storage created prior to schema versioning is rejected
+// on opening and that Migrate restores all records the current schema relies
+// on.
+func TestMigrate(t *testing.T) {
+	path := tempStorage(t)
+	deleted := createBaselineStorage(t, path)
+
+	// Opening an outdated storage fails, even read-only, and leaves it as is
+	_, err := NewDatabase(path, true)
+	assert.True(t, errors.Is(err, database.ErrSchemaVersion))
+	assert.False(t, hasVersion(t, path))
+
+	_, err = NewDatabase(path, false)
+	assert.True(t, errors.Is(err, database.ErrSchemaVersion))
+
+	assert.NoError(t, Migrate(path))
+
+	s, err := openStorage(path)
+	assert.NoError(t, err)
+
+	version, err := readSchemaVersion(s)
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+
+	// All records deleted from the storage are restored
+	for key, value := range deleted {
+		if bytes.HasPrefix([]byte(key), VersionPrefix) {
+			continue
+		}
+
+		v, err := s.Get([]byte(key), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, value, v)
+	}
+
+	assert.NoError(t, s.Close())
+
+	// Migrating a storage is idempotent
+	assert.NoError(t, Migrate(path))
+
+	db, err := NewDatabase(path, true)
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	assert.NoError(t, db.View(func(tx database.Transaction) error {
+		// Headers are iterated through the restored index
+		var count int
+		err := tx.IterateHeaders(0, 4, func(*block.Header) (bool, error) {
+			count++
+			return true, nil
+		})
+
+		assert.Equal(t, 5, count)
+
+		// Candidates are listed by round through the restored index
+		for round := uint64(1); round <= 5; round++ {
+			candidates, err := tx.FetchCandidateMessagesByRound(round)
+			assert.NoError(t, err)
+			assert.Len(t, candidates, 1)
+		}
+
+		return err
+	}))
+}
+
+// TestSchemaVersion ensures a new storage is marked with the schema version
+// only by a writable instance and that a newer storage is rejected.
+func TestSchemaVersion(t *testing.T) {
+	path := tempStorage(t)
+
+	db, err := NewDatabase(path, true)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+	assert.False(t, hasVersion(t, path))
+
+	db, err = NewDatabase(path, false)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+	assert.True(t, hasVersion(t, path))
+
+	s, err := openStorage(path)
+	assert.NoError(t, err)
+
+	versioned, err := checkSchema(s)
+	assert.NoError(t, err)
+	assert.True(t, versioned)
+
+	assert.NoError(t, writeSchemaVersion(s, SchemaVersion+1))
+
+	_, err = checkSchema(s)
+	assert.True(t, errors.Is(err, database.ErrSchemaVersion))
+
+	assert.NoError(t, s.Close())
+
+	err = Migrate(path)
+	assert.True(t, errors.Is(err, database.ErrSchemaVersion))
+}
@@ -0,0 +1,135 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// snapshotBatchSize is the number of records copied at once by
+// snapshot.Archive.
+const snapshotBatchSize = 1000
+
+// snapshot is a read-only transaction that can be archived.
+type snapshot struct {
+	*transaction
+}
+
+// Snapshot implements database.Snapshotter. LevelDB snapshots are cheap and do
+// not block writers, so the node can keep accepting blocks while a snapshot
+// is being archived.
+func (db DB) Snapshot() (database.Snapshot, error) {
+	t, err := db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot{t.(*transaction)}, nil
+}
+
+// Archive copies all snapshot records into a new LevelDB storage created in
+// a temporary directory and writes its files into the tar archive. LevelDB
+// files of a live storage cannot be archived directly as they are rewritten
+// by compactions at any time.
+func (s snapshot) Archive(tw *tar.Writer, dir string) error {
+	tmp, err := ioutil.TempDir("", "dusk-snapshot")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tmp)
+	}()
+
+	if err := s.copyTo(tmp); err != nil {
+		return err
+	}
+
+	return filepath.Walk(tmp, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// LOCK file is owned by the process that opens the storage
+		if !info.Mode().IsRegular() || info.Name() == "LOCK" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(tmp, p)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = path.Join(dir, filepath.ToSlash(rel))
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// copyTo copies all snapshot records into a new LevelDB storage located at
+// dir.
+func (s snapshot) copyTo(dir string) error {
+	dst, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return err
+	}
+
+	iter := s.snapshot.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+
+		if batch.Len() >= snapshotBatchSize {
+			if err := dst.Write(batch, nil); err != nil {
+				_ = dst.Close()
+				return err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+
+	if err := dst.Write(batch, nil); err != nil {
+		_ = dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
@@ -8,6 +8,8 @@ package heavy
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
@@ -58,6 +60,24 @@ var (
 	PersistedPrefix = []byte{0x06}
 	// CandidatePrefix is the prefix to identify Candidate messages.
 	CandidatePrefix = []byte{0x07}
+	// StateRootPrefix is the prefix to identify the height of a block by its state root.
+	StateRootPrefix = []byte{0x08}
+	// PrunedPrefix is the prefix to identify the height of the latest pruned block.
+	PrunedPrefix = []byte{0x09}
+	// HeaderHeightPrefix is the prefix to identify the Header hash by its
+	// Height, sorted in ascending order.
+	HeaderHeightPrefix = []byte{0x0A}
+	// NullifierPrefix is the prefix to identify the Transaction ID by a spent nullifier.
+	NullifierPrefix = []byte{0x0B}
+	// ContractPrefix is the prefix to identify the Transaction IDs by a called contract.
+	ContractPrefix = []byte{0x0C}
+	// VersionPrefix is the prefix to identify the schema version of the storage.
+	VersionPrefix = []byte{0x0D}
+	// CandidateRoundPrefix is the prefix to identify the Candidate messages of
+	// a round, sorted in ascending order.
+	CandidateRoundPrefix = []byte{0x0E}
+	// JournalPrefix is the prefix to identify the block being accepted.
+	JournalPrefix = []byte{0x0F}
 )
 
 type transaction struct {
@@ -109,11 +129,120 @@ func (t transaction) StoreBlock(b *block.Block, persisted bool) error {
 	// To support fetching blockchain persisted hash
 	if persisted {
 		t.put(PersistedPrefix, b.Header.Hash)
+
+		if t.db.pruneDepth > 0 {
+			return t.prune(b.Header.Height)
+		}
+	}
+
+	return nil
+}
+
+// maxPruneBlocks is the maximum number of blocks pruned on a single call of
+// StoreBlock. It keeps the batch bounded when pruning mode is enabled on an
+// existing storage, with many blocks left behind the prune depth.
+const maxPruneBlocks = 100
+
+// prune deletes transactions payload of blocks older than pruneDepth blocks
+// behind persistedHeight, up to maxPruneBlocks at a time. The genesis block is
+// never pruned. Headers and TxID records are kept so that a lookup of pruned
+// data results in ErrBlockPruned.
+func (t transaction) prune(persistedHeight uint64) error {
+	if persistedHeight <= t.db.pruneDepth {
+		return nil
+	}
+
+	to := persistedHeight - t.db.pruneDepth
+
+	// The genesis block is kept in full
+	from := uint64(1)
+
+	prunedHeight, pruned, err := t.fetchPrunedHeight()
+	if err != nil {
+		return err
+	}
+
+	if pruned {
+		from = prunedHeight + 1
+	}
+
+	if from > to {
+		return nil
+	}
+
+	// Any remainder is pruned on the next persisted blocks
+	if to-from >= maxPruneBlocks {
+		to = from + maxPruneBlocks - 1
+	}
+
+	for h := from; h <= to; h++ {
+		hash, err := t.FetchBlockHashByHeight(h)
+		if err != nil {
+			return err
+		}
+
+		iter := t.snapshot.NewIterator(util.BytesPrefix(append(TxPrefix, hash...)), nil)
+
+		for iter.Next() {
+			t.batch.Delete(iter.Key())
+		}
+
+		err = iter.Error()
+		iter.Release()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// Key = PrunedPrefix
+	// Value = height of the latest pruned block
+	heightBuf := new(bytes.Buffer)
+	if err := utils.WriteUint64(heightBuf, to); err != nil {
+		return err
 	}
 
+	t.put(PrunedPrefix, heightBuf.Bytes())
+
+	log.WithField("from", from).WithField("to", to).Debug("blocks pruned")
+
 	return nil
 }
 
+// fetchPrunedHeight returns the height of the latest pruned block, if any.
+func (t transaction) fetchPrunedHeight() (uint64, bool, error) {
+	value, err := t.snapshot.Get(PrunedPrefix, nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	var height uint64
+	if err := utils.ReadUint64(bytes.NewReader(value), &height); err != nil {
+		return 0, false, err
+	}
+
+	return height, true, nil
+}
+
+// isPruned checks if transactions payload of a block has been pruned.
+func (t transaction) isPruned(hash []byte) (bool, error) {
+	prunedHeight, pruned, err := t.fetchPrunedHeight()
+	if err != nil || !pruned {
+		return false, err
+	}
+
+	header, err := t.FetchBlockHeader(hash)
+	if err != nil {
+		return false, err
+	}
+
+	return header.Height > 0 && header.Height <= prunedHeight, nil
+}
+
 func (t *transaction) modify(optype int, b *block.Block) error {
 	if t.batch == nil {
 		// t.batch is initialized only on a open, read-write transaction
@@ -178,6 +307,10 @@ func (t *transaction) modify(optype int, b *block.Block) error {
 		//
 		// For the retrival of a single transaction by TxId
 		t.op(optype, append(TxIDPrefix, txID...), b.Header.Hash)
+
+		if err := t.modifyTxIndices(optype, tx, txID, b.Header.Height, uint32(i)); err != nil {
+			return err
+		}
 	}
 
 	// Key = HeightPrefix + block.header.height
@@ -195,9 +328,88 @@ func (t *transaction) modify(optype int, b *block.Block) error {
 
 	t.op(optype, key, b.Header.Hash)
 
+	// Key = HeaderHeightPrefix + block.header.height (big endian)
+	// Value = block.header.hash
+	//
+	// To support range iteration of headers. Unlike HeightPrefix records,
+	// keys are sorted by height.
+	t.op(optype, headerHeightKey(b.Header.Height), b.Header.Hash)
+
+	// Key = StateRootPrefix + block.header.state_hash
+	// Value = block.header.height
+	//
+	// To support fast block lookup by state root
+	if len(b.Header.StateHash) > 0 {
+		key = append(StateRootPrefix, b.Header.StateHash...)
+
+		if optype == optypeDelete {
+			// A state root might be shared by more than one block. Delete
+			// the entry only if it still points at this block.
+			value, err := t.snapshot.Get(key, nil)
+			if err != nil || !bytes.Equal(value, heightBuf.Bytes()) {
+				return nil
+			}
+		}
+
+		t.op(optype, key, heightBuf.Bytes())
+	}
+
 	return nil
 }
 
+// modifyTxIndices puts or deletes the records of the secondary tx indices, if
+// enabled.
+func (t transaction) modifyTxIndices(optype int, tx transactions.ContractCall, txID []byte, height uint64, txIndex uint32) error {
+	if !t.db.indexNullifiers && !t.db.indexContracts {
+		return nil
+	}
+
+	decoded, err := tx.Decode()
+	if err != nil {
+		// A tx with undecodable payload cannot be indexed but it is still a
+		// valid chain tx.
+		log.WithError(err).WithField("txid", hex.EncodeToString(txID)).Warn("tx not indexed")
+		return nil
+	}
+
+	// Schema
+	//
+	// Key = NullifierPrefix + nullifier
+	// Value = txID
+	//
+	// For the retrival of a single transaction by a spent nullifier
+	if t.db.indexNullifiers {
+		for _, nullifier := range decoded.Nullifiers {
+			t.op(optype, append(NullifierPrefix, nullifier...), txID)
+		}
+	}
+
+	// Schema
+	//
+	// Key = ContractPrefix + contractID + height (big endian) + index (big endian)
+	// Value = txID
+	//
+	// For the retrival of all transactions calling a contract, sorted by
+	// height and index
+	if t.db.indexContracts && decoded.Call != nil {
+		t.op(optype, contractKey(decoded.Call.ContractID, height, txIndex), txID)
+	}
+
+	return nil
+}
+
+func contractKey(contractID []byte, height uint64, txIndex uint32) []byte {
+	key := make([]byte, len(ContractPrefix)+len(contractID)+12)
+
+	n := copy(key, ContractPrefix)
+	n += copy(key[n:], contractID)
+
+	binary.BigEndian.PutUint64(key[n:], height)
+	binary.BigEndian.PutUint32(key[n+8:], txIndex)
+
+	return key
+}
+
 // Commit writes a batch to LevelDB storage. See also fsyncEnabled variable.
 func (t *transaction) Commit() error {
 	if !t.writable {
@@ -292,6 +504,18 @@ func (t transaction) FetchBlockTxs(hashHeader []byte) ([]transactions.ContractCa
 		tempTxs[txIndex] = tx
 	}
 
+	if len(tempTxs) == 0 {
+		// No transactions found. Either an empty block or a pruned one.
+		pruned, err := t.isPruned(hashHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		if pruned {
+			return nil, database.ErrBlockPruned
+		}
+	}
+
 	// Reorder Tx slice as per retrieved indexes
 	resultTxs := make([]transactions.ContractCall, len(tempTxs))
 	for k, v := range tempTxs {
@@ -323,6 +547,70 @@ func (t transaction) FetchBlockHashByHeight(height uint64) ([]byte, error) {
 	return value, nil
 }
 
+// IterateHeaders iterates the headers of a height range with a single
+// LevelDB range iterator. Blocks stored prior to HeaderHeightPrefix index are
+// looked up by height instead.
+func (t transaction) IterateHeaders(from, to uint64, fn func(*block.Header) (bool, error)) error {
+	if from > to {
+		return nil
+	}
+
+	r := &util.Range{Start: headerHeightKey(from)}
+	if to < math.MaxUint64 {
+		r.Limit = headerHeightKey(to + 1)
+	} else {
+		r.Limit = []byte{HeaderHeightPrefix[0] + 1}
+	}
+
+	iter := t.snapshot.NewIterator(r, nil)
+	defer iter.Release()
+
+	valid := iter.Next()
+
+	for height := from; ; height++ {
+		var hash []byte
+
+		if valid && binary.BigEndian.Uint64(iter.Key()[len(HeaderHeightPrefix):]) == height {
+			// The value is reused by the iterator on Next
+			hash = append([]byte{}, iter.Value()...)
+			valid = iter.Next()
+		} else {
+			var err error
+
+			hash, err = t.FetchBlockHashByHeight(height)
+			if err == database.ErrBlockNotFound {
+				// Chain tip passed
+				return iter.Error()
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := t.FetchBlockHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		next, err := fn(header)
+		if err != nil || !next {
+			return err
+		}
+
+		if height == to {
+			return iter.Error()
+		}
+	}
+}
+
+func headerHeightKey(height uint64) []byte {
+	key := make([]byte, len(HeaderHeightPrefix)+8)
+	copy(key, HeaderHeightPrefix)
+	binary.BigEndian.PutUint64(key[len(HeaderHeightPrefix):], height)
+	return key
+}
+
 func (t transaction) put(key []byte, value []byte) {
 	if !t.writable {
 		return
@@ -354,6 +642,21 @@ func (t transaction) op(opType int, key []byte, value []byte) {
 	}
 }
 
+// IterateTxIDs iterates TxIDPrefix records with a single LevelDB iterator.
+func (t transaction) IterateTxIDs(fn func(txID, blockHash []byte) (bool, error)) error {
+	iter := t.snapshot.NewIterator(util.BytesPrefix(TxIDPrefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		next, err := fn(iter.Key()[len(TxIDPrefix):], iter.Value())
+		if err != nil || !next {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
 func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall, uint32, []byte, error) {
 	txIndex := uint32(math.MaxUint32)
 
@@ -404,9 +707,49 @@ func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall,
 		return tx, idx, hashHeader, nil
 	}
 
+	if pruned, err := t.isPruned(hashHeader); err == nil && pruned {
+		return nil, txIndex, nil, database.ErrBlockPruned
+	}
+
 	return nil, txIndex, nil, errors.New("block tx is available but fetching it fails")
 }
 
+func (t transaction) FetchTxByNullifier(nullifier []byte) (transactions.ContractCall, uint32, []byte, error) {
+	if !t.db.indexNullifiers {
+		return nil, math.MaxUint32, nil, database.ErrIndexDisabled
+	}
+
+	txID, err := t.snapshot.Get(append(NullifierPrefix, nullifier...), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			// overwrite error message
+			err = database.ErrTxNotFound
+		}
+
+		return nil, math.MaxUint32, nil, err
+	}
+
+	return t.FetchBlockTxByHash(txID)
+}
+
+func (t transaction) FetchTxsByContract(contractID []byte, limit int) ([][]byte, error) {
+	if !t.db.indexContracts {
+		return nil, database.ErrIndexDisabled
+	}
+
+	txIDs := make([][]byte, 0)
+
+	iter := t.snapshot.NewIterator(util.BytesPrefix(append(ContractPrefix, contractID...)), nil)
+	defer iter.Release()
+
+	// Latest txs first
+	for ok := iter.Last(); ok && len(txIDs) < limit; ok = iter.Prev() {
+		txIDs = append(txIDs, append([]byte{}, iter.Value()...))
+	}
+
+	return txIDs, iter.Error()
+}
+
 func (t transaction) FetchBlock(hash []byte) (*block.Block, error) {
 	header, err := t.FetchBlockHeader(hash)
 	if err != nil {
@@ -445,9 +788,16 @@ func (t transaction) FetchRegistry() (*database.Registry, error) {
 		return nil, err
 	}
 
+	prunedHeight, pruned, err := t.fetchPrunedHeight()
+	if err != nil {
+		return nil, err
+	}
+
 	return &database.Registry{
 		TipHash:       tipHash,
 		PersistedHash: persistedHash,
+		Pruned:        pruned,
+		PrunedHeight:  prunedHeight,
 	}, nil
 }
 
@@ -502,8 +852,16 @@ func (t transaction) StoreCandidateMessage(cm block.Block) error {
 		return err
 	}
 
+	// Key = CandidatePrefix + block.header.hash
+	// Value = block.Encode()
 	key := append(CandidatePrefix, cm.Header.Hash...)
 	t.put(key, buf.Bytes())
+
+	// Key = CandidateRoundPrefix + block.header.height (big endian) + block.header.hash
+	// Value = empty
+	//
+	// To support listing and pruning of candidates by round
+	t.put(candidateRoundKey(cm.Header.Height, cm.Header.Hash), []byte{})
 	return nil
 }
 
@@ -523,9 +881,73 @@ func (t transaction) FetchCandidateMessage(hash []byte) (block.Block, error) {
 	return *cm, nil
 }
 
+// FetchCandidateMessagesByRound returns all candidate blocks of a round.
+func (t transaction) FetchCandidateMessagesByRound(round uint64) ([]block.Block, error) {
+	iter := t.snapshot.NewIterator(util.BytesPrefix(candidateRoundKey(round, nil)), nil)
+	defer iter.Release()
+
+	cms := make([]block.Block, 0)
+
+	for iter.Next() {
+		hash := iter.Key()[len(CandidateRoundPrefix)+8:]
+
+		cm, err := t.FetchCandidateMessage(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		cms = append(cms, cm)
+	}
+
+	return cms, iter.Error()
+}
+
+// DeleteCandidateMessages deletes all candidate blocks of the rounds up to
+// maxRound (included).
+func (t transaction) DeleteCandidateMessages(maxRound uint64) error {
+	r := &util.Range{
+		Start: CandidateRoundPrefix,
+		Limit: candidateRoundKey(maxRound+1, nil),
+	}
+
+	if maxRound == math.MaxUint64 {
+		r = util.BytesPrefix(CandidateRoundPrefix)
+	}
+
+	iter := t.snapshot.NewIterator(r, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		hash := iter.Key()[len(CandidateRoundPrefix)+8:]
+
+		t.batch.Delete(append(CandidatePrefix, hash...))
+		t.batch.Delete(iter.Key())
+	}
+
+	return iter.Error()
+}
+
+// candidateRoundKey returns the CandidateRoundPrefix key of a candidate. It
+// returns the prefix of all candidates of the round, if hash is nil.
+func candidateRoundKey(round uint64, hash []byte) []byte {
+	key := make([]byte, len(CandidateRoundPrefix)+8+len(hash))
+
+	n := copy(key, CandidateRoundPrefix)
+	binary.BigEndian.PutUint64(key[n:], round)
+	copy(key[n+8:], hash)
+
+	return key
+}
+
 // FetchBlockByStateRoot finds a block that is linked to a specified state_root.
-// Loop through all blocks in reverse order.
+// It looks up the StateRoot index first. If the index cannot resolve it (e.g
+// blocks stored prior to the index), it loops through all blocks in reverse
+// order.
 func (t *transaction) FetchBlockByStateRoot(fromHeight uint64, stateRoot []byte) (*block.Block, error) {
+	if b, err := t.fetchBlockByStateRootIndex(fromHeight, stateRoot); err == nil {
+		return b, nil
+	}
+
 	i := fromHeight
 
 	for {
@@ -563,23 +985,114 @@ func (t *transaction) FetchBlockByStateRoot(fromHeight uint64, stateRoot []byte)
 	}
 }
 
+// fetchBlockByStateRootIndex resolves a state_root into a block with a single
+// lookup of the StateRoot index.
+func (t *transaction) fetchBlockByStateRootIndex(fromHeight uint64, stateRoot []byte) (*block.Block, error) {
+	key := append(StateRootPrefix, stateRoot...)
+
+	value, err := t.snapshot.Get(key, nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			// overwrite error message
+			err = database.ErrStateHashNotFound
+		}
+
+		return nil, err
+	}
+
+	var height uint64
+	if err = utils.ReadUint64(bytes.NewReader(value), &height); err != nil {
+		return nil, err
+	}
+
+	if height > fromHeight {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	hash, err := t.FetchBlockHashByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := t.FetchBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sanity check in case of a stale index entry
+	if !bytes.Equal(b.Header.StateHash, stateRoot) {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	return b, nil
+}
+
 func (t transaction) ClearCandidateMessages() error {
-	iter := t.snapshot.NewIterator(util.BytesPrefix(CandidatePrefix), nil)
-	defer iter.Release()
+	for _, prefix := range [][]byte{CandidatePrefix, CandidateRoundPrefix} {
+		iter := t.snapshot.NewIterator(util.BytesPrefix(prefix), nil)
 
-	for iter.Next() {
-		t.batch.Delete(iter.Key())
+		for iter.Next() {
+			t.batch.Delete(iter.Key())
+		}
+
+		err := iter.Error()
+		iter.Release()
+
+		if err != nil {
+			return err
+		}
 	}
 
-	return iter.Error()
+	return nil
+}
+
+// StoreJournal records the block being accepted.
+func (t transaction) StoreJournal(b *block.Block) error {
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, b); err != nil {
+		return err
+	}
+
+	t.put(JournalPrefix, buf.Bytes())
+	return nil
+}
+
+// FetchJournal returns the block being accepted, if any.
+func (t transaction) FetchJournal() (*block.Block, error) {
+	value, err := t.snapshot.Get(JournalPrefix, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, database.ErrJournalEmpty
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(value), b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// DeleteJournal deletes the journal.
+func (t transaction) DeleteJournal() error {
+	t.batch.Delete(JournalPrefix)
+	return nil
 }
 
-// ClearDatabase will wipe all of the data currently in the database.
+// ClearDatabase will wipe all of the data currently in the database. The
+// schema version is kept.
 func (t transaction) ClearDatabase() error {
 	iter := t.snapshot.NewIterator(nil, nil)
 	defer iter.Release()
 
 	for iter.Next() {
+		if bytes.Equal(iter.Key(), VersionPrefix) {
+			continue
+		}
+
 		t.batch.Delete(iter.Key())
 	}
 
@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package heavy
+
+import (
+	"testing"
+
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	assert "github.com/stretchr/testify/require"
+)
+
+// TestPruneBounded ensures enabling pruning mode on a storage with many blocks
+// behind the prune depth prunes them in bounded chunks, and never the genesis
+// block.
+func TestPruneBounded(t *testing.T) {
+	orig := cfg.Get()
+	defer cfg.Mock(&orig)
+
+	r := cfg.Get()
+	r.Database.PruneDepth = 1
+	cfg.Mock(&r)
+
+	db, err := NewDatabase(tempStorage(t), false)
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	// Blocks from height 1 to 2*maxPruneBlocks are behind the prune depth
+	tip := uint64(2*maxPruneBlocks + 1)
+
+	assert.NoError(t, db.Update(func(tx database.Transaction) error {
+		for h := uint64(0); h < tip; h++ {
+			if err := tx.StoreBlock(helper.RandomBlock(h, 1), false); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	for i := uint64(1); i <= 2; i++ {
+		assert.NoError(t, db.Update(func(tx database.Transaction) error {
+			return tx.StoreBlock(helper.RandomBlock(tip+i-1, 1), true)
+		}))
+
+		assert.NoError(t, db.View(func(tx database.Transaction) error {
+			r, err := tx.FetchRegistry()
+			assert.NoError(t, err)
+			assert.Equal(t, i*maxPruneBlocks, r.PrunedHeight)
+
+			hash, err := tx.FetchBlockHashByHeight(0)
+			assert.NoError(t, err)
+
+			_, err = tx.FetchBlockTxs(hash)
+			assert.NoError(t, err)
+
+			hash, err = tx.FetchBlockHashByHeight(r.PrunedHeight)
+			assert.NoError(t, err)
+
+			_, err = tx.FetchBlockTxs(hash)
+			assert.Equal(t, database.ErrBlockPruned, err)
+			return nil
+		}))
+	}
+}
@@ -7,6 +7,7 @@
 package database
 
 import (
+	"archive/tar"
 	"errors"
 	"math"
 
@@ -28,6 +29,17 @@ var (
 	ErrOutputNotFound = errors.New("database: output not found")
 	// ErrStateHashNotFound returned on state hash not linked to any block.
 	ErrStateHashNotFound = errors.New("database: state hash was not found")
+	// ErrBlockPruned returned on a lookup of transactions of a pruned block.
+	ErrBlockPruned = errors.New("database: block transactions were pruned")
+	// ErrIndexDisabled returned on a lookup of a secondary index that is not
+	// enabled.
+	ErrIndexDisabled = errors.New("database: index is disabled")
+	// ErrSchemaVersion returned on opening a storage of an incompatible
+	// schema version.
+	ErrSchemaVersion = errors.New("database: incompatible schema version")
+	// ErrJournalEmpty returned on a journal lookup if no block is being
+	// accepted.
+	ErrJournalEmpty = errors.New("database: journal is empty")
 
 	// AnyTxType is used as a filter value on FetchBlockTxByHash.
 	AnyTxType = transactions.TxType(math.MaxUint8)
@@ -67,6 +79,25 @@ type Transaction interface {
 	FetchBlockExists(hash []byte) (bool, error)
 	FetchBlockByStateRoot(fromHeight uint64, stateRoot []byte) (*block.Block, error)
 
+	// Fetch a confirmed tx that spent a nullifier. If succeeds, it returns tx
+	// data, tx index and hash of the block it belongs to.
+	FetchTxByNullifier(nullifier []byte) (tx transactions.ContractCall, txIndex uint32, blockHeaderHash []byte, err error)
+	// Fetch IDs of up to limit latest confirmed txs calling a contract,
+	// sorted by block height and tx index in descending order.
+	FetchTxsByContract(contractID []byte, limit int) ([][]byte, error)
+
+	// IterateHeaders calls fn for each block header in the height range
+	// [from, to] in ascending order. Iteration stops at the first missing
+	// height (e.g. the chain tip is passed), when fn returns false or when fn
+	// returns an error. The error is returned by IterateHeaders.
+	IterateHeaders(from, to uint64, fn func(*block.Header) (bool, error)) error
+
+	// IterateTxIDs calls fn for each record of the TxID index with the TxID
+	// and the hash of the block it points to, in no particular order. The
+	// slices are only valid until fn returns. Iteration stops when fn returns
+	// false or an error. The error is returned by IterateTxIDs.
+	IterateTxIDs(fn func(txID, blockHash []byte) (bool, error)) error
+
 	// Fetch chain registry (chain tip hash, persisted block etc).
 	FetchRegistry() (*Registry, error)
 
@@ -90,13 +121,36 @@ type Transaction interface {
 	// sinceUnixTime starting the search from height (tip - offset).
 	FetchBlockHeightSince(sinceUnixTime int64, offset uint64) (uint64, error)
 
-	// StoreCandidateMessage will...
+	// StoreCandidateMessage stores a candidate block along with its round
+	// (the candidate height).
 	StoreCandidateMessage(cm block.Block) error
 
+	// FetchCandidateMessage returns a candidate block by its hash.
 	FetchCandidateMessage(hash []byte) (block.Block, error)
 
+	// FetchCandidateMessagesByRound returns all candidate blocks of a round.
+	FetchCandidateMessagesByRound(round uint64) ([]block.Block, error)
+
+	// DeleteCandidateMessages deletes all candidate blocks of the rounds up
+	// to maxRound (included).
+	DeleteCandidateMessages(maxRound uint64) error
+
+	// ClearCandidateMessages deletes all candidate blocks.
 	ClearCandidateMessages() error
 
+	// StoreJournal records the block being accepted, before any state
+	// transition is run. The block header holds both the height and the
+	// expected state root.
+	StoreJournal(b *block.Block) error
+
+	// FetchJournal returns the block being accepted. It returns
+	// ErrJournalEmpty if there is none.
+	FetchJournal() (*block.Block, error)
+
+	// DeleteJournal deletes the journal. It should be called in the same
+	// transaction the accepted block is stored.
+	DeleteJournal() error
+
 	// ClearDatabase will remove all information from the database.
 	ClearDatabase() error
 
@@ -123,8 +177,31 @@ type DB interface {
 	Close() error
 }
 
+// Snapshot is a read-only Transaction over a consistent view of the storage
+// that can be archived while the storage is in use.
+type Snapshot interface {
+	Transaction
+
+	// Archive writes a copy of the storage, as of the snapshot, into the tar
+	// archive under the directory dir. The copy can be opened by the driver
+	// that created it.
+	Archive(tw *tar.Writer, dir string) error
+}
+
+// Snapshotter is implemented by a DB that supports online backups.
+type Snapshotter interface {
+	// Snapshot returns a Snapshot of the storage. It must be closed once done.
+	Snapshot() (Snapshot, error)
+}
+
 // Registry represents a set database records that provide chain metadata.
 type Registry struct {
 	TipHash       []byte
 	PersistedHash []byte
+
+	// Pruned is true once transactions of blocks from height 1 up to
+	// PrunedHeight (included) have been pruned. The genesis block is never
+	// pruned.
+	Pruned       bool
+	PrunedHeight uint64
 }
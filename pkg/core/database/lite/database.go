@@ -9,6 +9,7 @@ package lite
 import (
 	"sync"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 )
 
@@ -27,6 +28,10 @@ const (
 	stateInd
 	candidateInd
 	persistedInd
+	stateRootInd
+	nullifierInd
+	contractInd
+	journalInd
 	maxInd
 )
 
@@ -38,6 +43,10 @@ type DB struct {
 	mu       sync.RWMutex
 	readOnly bool
 	path     string
+
+	// Secondary tx indices enabled.
+	indexNullifiers bool
+	indexContracts  bool
 }
 
 // NewDatabase returns a DB instance.
@@ -52,7 +61,15 @@ func NewDatabase(path string, readonly bool) (database.DB, error) {
 		tables[i] = make(table)
 	}
 
-	db = &DB{path: path, readOnly: readonly, storage: tables}
+	conf := cfg.Get().Database
+
+	db = &DB{
+		path:            path,
+		readOnly:        readonly,
+		storage:         tables,
+		indexNullifiers: conf.IndexNullifiers,
+		indexContracts:  conf.IndexContracts,
+	}
 
 	return db, nil
 }
@@ -8,9 +8,11 @@ package lite
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
@@ -25,7 +27,42 @@ type transaction struct {
 	batch    memdb
 }
 
+// DeleteBlock marks all records associated with a specified block for
+// deletion on Commit.
 func (t *transaction) DeleteBlock(b *block.Block) error {
+	if !t.writable {
+		return errors.New("read-only transaction")
+	}
+
+	for i, tx := range b.Txs {
+		txID, err := tx.CalculateHash()
+		if err != nil {
+			return err
+		}
+
+		t.batch[txsInd][toKey(txID)] = nil
+		t.batch[txHashInd][toKey(txID)] = nil
+
+		t.indexTx(tx, nil, b.Header.Height, uint32(i))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := utils.WriteUint64(buf, b.Header.Height); err != nil {
+		return err
+	}
+
+	t.batch[blocksInd][toKey(b.Header.Hash)] = nil
+	t.batch[heightInd][toKey(buf.Bytes())] = nil
+
+	// A state root might be shared by more than one block. Delete the entry
+	// only if it still points at this block.
+	if len(b.Header.StateHash) > 0 {
+		k := toKey(b.Header.StateHash)
+		if height, ok := t.db.storage[stateRootInd][k]; ok && bytes.Equal(height, buf.Bytes()) {
+			t.batch[stateRootInd][k] = nil
+		}
+	}
+
 	return nil
 }
 
@@ -70,6 +107,8 @@ func (t *transaction) StoreBlock(b *block.Block, persisted bool) error {
 
 		t.batch[txsInd][toKey(txID)] = data
 		t.batch[txHashInd][toKey(txID)] = b.Header.Hash
+
+		t.indexTx(tx, txID, b.Header.Height, uint32(i))
 	}
 
 	// Map height to buffer bytes
@@ -82,6 +121,11 @@ func (t *transaction) StoreBlock(b *block.Block, persisted bool) error {
 
 	t.batch[heightInd][toKey(buf.Bytes())] = blockBytes
 
+	// Map state root to height
+	if len(b.Header.StateHash) > 0 {
+		t.batch[stateRootInd][toKey(b.Header.StateHash)] = buf.Bytes()
+	}
+
 	// Map stateKey to chain state (tip)
 	t.batch[stateInd][toKey(stateKey)] = b.Header.Hash
 
@@ -101,6 +145,12 @@ func (t *transaction) Commit() error {
 	/// commit changes
 	for i := range t.db.storage {
 		for k, v := range t.batch[i] {
+			// nil value marks a deleted record
+			if v == nil {
+				delete(t.db.storage[i], k)
+				continue
+			}
+
 			t.db.storage[i][k] = v
 		}
 	}
@@ -147,6 +197,39 @@ func (t transaction) FetchBlockTxs(hash []byte) ([]transactions.ContractCall, er
 	return b.Txs, nil
 }
 
+// IterateHeaders looks up each height of the range, as memdb tables are not
+// sorted.
+func (t transaction) IterateHeaders(from, to uint64, fn func(*block.Header) (bool, error)) error {
+	for height := from; height <= to; height++ {
+		heightBuf := new(bytes.Buffer)
+		if err := utils.WriteUint64(heightBuf, height); err != nil {
+			return err
+		}
+
+		data, exists := t.db.storage[heightInd][toKey(heightBuf.Bytes())]
+		if !exists {
+			// Chain tip passed
+			return nil
+		}
+
+		b := block.NewBlock()
+		if err := message.UnmarshalBlock(bytes.NewBuffer(data), b); err != nil {
+			return err
+		}
+
+		next, err := fn(b.Header)
+		if err != nil || !next {
+			return err
+		}
+
+		if height == math.MaxUint64 {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 func (t transaction) FetchBlockHashByHeight(height uint64) ([]byte, error) {
 	heightBuf := new(bytes.Buffer)
 
@@ -170,6 +253,19 @@ func (t transaction) FetchBlockHashByHeight(height uint64) ([]byte, error) {
 	return b.Header.Hash, nil
 }
 
+// IterateTxIDs iterates the TxID index. Keys are padded, TxIDs being 32 bytes
+// long.
+func (t transaction) IterateTxIDs(fn func(txID, blockHash []byte) (bool, error)) error {
+	for k, hash := range t.db.storage[txHashInd] {
+		next, err := fn(k[:32], hash)
+		if err != nil || !next {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall, uint32, []byte, error) {
 	var data []byte
 	var exists bool
@@ -192,6 +288,53 @@ func (t transaction) FetchBlockTxByHash(txID []byte) (transactions.ContractCall,
 	return tx, txIndex, hash, err
 }
 
+func (t transaction) FetchTxByNullifier(nullifier []byte) (transactions.ContractCall, uint32, []byte, error) {
+	if !t.db.indexNullifiers {
+		return nil, math.MaxUint32, nil, database.ErrIndexDisabled
+	}
+
+	txID, exists := t.db.storage[nullifierInd][toKey(nullifier)]
+	if !exists {
+		return nil, math.MaxUint32, nil, database.ErrTxNotFound
+	}
+
+	return t.FetchBlockTxByHash(txID)
+}
+
+func (t transaction) FetchTxsByContract(contractID []byte, limit int) ([][]byte, error) {
+	if !t.db.indexContracts {
+		return nil, database.ErrIndexDisabled
+	}
+
+	keys := make([]key, 0)
+
+	for k := range t.db.storage[contractInd] {
+		if bytes.HasPrefix(k[:], contractID) {
+			keys = append(keys, k)
+		}
+	}
+
+	// Latest txs first
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) > 0
+	})
+
+	if limit < 0 {
+		limit = 0
+	}
+
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	txIDs := make([][]byte, len(keys))
+	for i, k := range keys {
+		txIDs[i] = t.db.storage[contractInd][k]
+	}
+
+	return txIDs, nil
+}
+
 func (t transaction) FetchRegistry() (*database.Registry, error) {
 	var hash []byte
 	var exists bool
@@ -220,6 +363,41 @@ func (t transaction) FetchRegistry() (*database.Registry, error) {
 	return s, nil
 }
 
+// indexTx maps nullifiers and called contract of a tx to txID, if the
+// respective index is enabled. A nil txID marks the records as deleted.
+func (t *transaction) indexTx(tx transactions.ContractCall, txID []byte, height uint64, txIndex uint32) {
+	if !t.db.indexNullifiers && !t.db.indexContracts {
+		return
+	}
+
+	decoded, err := tx.Decode()
+	if err != nil {
+		return
+	}
+
+	if t.db.indexNullifiers {
+		for _, nullifier := range decoded.Nullifiers {
+			t.batch[nullifierInd][toKey(nullifier)] = txID
+		}
+	}
+
+	if t.db.indexContracts && decoded.Call != nil {
+		t.batch[contractInd][contractKey(decoded.Call.ContractID, height, txIndex)] = txID
+	}
+}
+
+// contractKey builds a contractInd key. Keys of the same contract are sorted
+// by height and tx index.
+func contractKey(contractID []byte, height uint64, txIndex uint32) key {
+	var k key
+
+	n := copy(k[:], contractID)
+	binary.BigEndian.PutUint64(k[n:], height)
+	binary.BigEndian.PutUint32(k[n+8:], txIndex)
+
+	return k
+}
+
 func toKey(d []byte) key {
 	var k key
 	copy(k[:], d)
@@ -321,9 +499,54 @@ func (t *transaction) FetchCandidateMessage(hash []byte) (block.Block, error) {
 	return *cm, nil
 }
 
+// FetchCandidateMessagesByRound returns all candidate blocks of a round, sorted
+// by hash.
+func (t *transaction) FetchCandidateMessagesByRound(round uint64) ([]block.Block, error) {
+	cms := make([]block.Block, 0)
+
+	for _, cmBytes := range t.db.storage[candidateInd] {
+		cm := block.NewBlock()
+		if err := message.UnmarshalBlock(bytes.NewBuffer(cmBytes), cm); err != nil {
+			return nil, err
+		}
+
+		if cm.Header.Height == round {
+			cms = append(cms, *cm)
+		}
+	}
+
+	sort.Slice(cms, func(i, j int) bool {
+		return bytes.Compare(cms[i].Header.Hash, cms[j].Header.Hash) < 0
+	})
+
+	return cms, nil
+}
+
+// DeleteCandidateMessages deletes all candidate blocks of the rounds up to
+// maxRound (included).
+func (t *transaction) DeleteCandidateMessages(maxRound uint64) error {
+	for k, cmBytes := range t.db.storage[candidateInd] {
+		header := block.NewHeader()
+		if err := message.UnmarshalHeader(bytes.NewBuffer(cmBytes), header); err != nil {
+			return err
+		}
+
+		if header.Height <= maxRound {
+			delete(t.db.storage[candidateInd], k)
+		}
+	}
+
+	return nil
+}
+
 // FetchBlockByStateRoot finds a block that is linked to a specified state_root.
-// Loop through all blocks in reverse order.
+// It looks up the state root index first and loops through all blocks in
+// reverse order only if the index cannot resolve it.
 func (t *transaction) FetchBlockByStateRoot(fromHeight uint64, stateRoot []byte) (*block.Block, error) {
+	if b, err := t.fetchBlockByStateRootIndex(fromHeight, stateRoot); err == nil {
+		return b, nil
+	}
+
 	i := fromHeight
 
 	for {
@@ -361,6 +584,38 @@ func (t *transaction) FetchBlockByStateRoot(fromHeight uint64, stateRoot []byte)
 	}
 }
 
+func (t *transaction) fetchBlockByStateRootIndex(fromHeight uint64, stateRoot []byte) (*block.Block, error) {
+	value, exists := t.db.storage[stateRootInd][toKey(stateRoot)]
+	if !exists {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	var height uint64
+	if err := utils.ReadUint64(bytes.NewReader(value), &height); err != nil {
+		return nil, err
+	}
+
+	if height > fromHeight {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	hash, err := t.FetchBlockHashByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := t.FetchBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(b.Header.StateHash, stateRoot) {
+		return nil, database.ErrStateHashNotFound
+	}
+
+	return b, nil
+}
+
 func (t *transaction) ClearCandidateMessages() error {
 	for k := range t.db.storage[candidateInd] {
 		delete(t.db.storage[candidateInd], k)
@@ -369,6 +624,36 @@ func (t *transaction) ClearCandidateMessages() error {
 	return nil
 }
 
+func (t *transaction) StoreJournal(b *block.Block) error {
+	buf := new(bytes.Buffer)
+	if err := message.MarshalBlock(buf, b); err != nil {
+		return err
+	}
+
+	t.batch[journalInd][toKey(stateKey)] = buf.Bytes()
+	return nil
+}
+
+func (t *transaction) FetchJournal() (*block.Block, error) {
+	value, ok := t.db.storage[journalInd][toKey(stateKey)]
+	if !ok {
+		return nil, database.ErrJournalEmpty
+	}
+
+	b := block.NewBlock()
+	if err := message.UnmarshalBlock(bytes.NewBuffer(value), b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (t *transaction) DeleteJournal() error {
+	// nil value marks a deleted record
+	t.batch[journalInd][toKey(stateKey)] = nil
+	return nil
+}
+
 func (t transaction) ClearDatabase() error {
 	for key := range t.db.storage {
 		t.db.storage[key] = make(table)
@@ -7,27 +7,33 @@
 package test
 
 import (
+	"archive/tar"
 	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	"github.com/syndtr/goleveldb/leveldb"
 
 	// Import here any supported drivers to verify if they are fully compliant
 	// to the blockchain database layer requirements.
+	_ "github.com/dusk-network/dusk-blockchain/pkg/core/database/bolt"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	crypto "github.com/dusk-network/dusk-crypto/hash"
@@ -53,6 +59,12 @@ var (
 func TestMain(m *testing.M) {
 	var code int
 
+	// Enable all optional indices.
+	r := cfg.Get()
+	r.Database.IndexNullifiers = true
+	r.Database.IndexContracts = true
+	cfg.Mock(&r)
+
 	// Run on all registered drivers.
 	for _, driverName := range database.Drivers() {
 		code = _TestDriver(m, driverName)
@@ -650,6 +662,579 @@ func TestFetchBlockTxByHash(test *testing.T) {
 	})
 }
 
+func TestFetchBlockByStateRoot(test *testing.T) {
+	genBlocks, err := generateChainBlocks(5)
+	require.NoError(test, err)
+
+	for _, b := range genBlocks {
+		b.Header.StateHash, _ = crypto.RandEntropy(32)
+	}
+
+	require.NoError(test, storeBlocks(db, genBlocks))
+
+	tip := genBlocks[len(genBlocks)-1]
+
+	// Ensure each block can be found by its state root
+	err = db.View(func(t database.Transaction) error {
+		for _, b := range genBlocks {
+			fetched, err := t.FetchBlockByStateRoot(tip.Header.Height, b.Header.StateHash)
+			if err != nil {
+				return err
+			}
+
+			if !bytes.Equal(fetched.Header.Hash, b.Header.Hash) {
+				return fmt.Errorf("invalid block fetched on height %d", b.Header.Height)
+			}
+		}
+
+		return nil
+	})
+	require.NoError(test, err)
+
+	// Ensure a deleted block cannot be found by its state root
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.DeleteBlock(tip)
+	}))
+
+	err = db.View(func(t database.Transaction) error {
+		_, err := t.FetchBlockByStateRoot(tip.Header.Height, tip.Header.StateHash)
+		return err
+	})
+	require.Error(test, err)
+
+	// restore chain tip for the other tests
+	require.NoError(test, storeBlocks(db, []*block.Block{tip}))
+}
+
+func TestIterateHeaders(test *testing.T) {
+	from := blocks[0].Header.Height
+	to := blocks[len(blocks)-1].Header.Height
+
+	// Iterate the entire range
+	headers := make([]*block.Header, 0)
+	err := db.View(func(t database.Transaction) error {
+		return t.IterateHeaders(from, to, func(header *block.Header) (bool, error) {
+			headers = append(headers, header)
+			return true, nil
+		})
+	})
+	require.NoError(test, err)
+	require.Len(test, headers, len(blocks))
+
+	for i, header := range headers {
+		require.True(test, header.Equals(blocks[i].Header))
+	}
+
+	// Stop iteration earlier
+	var count int
+	err = db.View(func(t database.Transaction) error {
+		return t.IterateHeaders(from, to, func(header *block.Header) (bool, error) {
+			count++
+			return count < 3, nil
+		})
+	})
+	require.NoError(test, err)
+	require.Equal(test, 3, count)
+
+	// An error is propagated
+	forcedError := errors.New("forced error")
+	err = db.View(func(t database.Transaction) error {
+		return t.IterateHeaders(from, to, func(header *block.Header) (bool, error) {
+			return true, forcedError
+		})
+	})
+	require.Equal(test, forcedError, err)
+}
+
+func TestIterateTxIDs(test *testing.T) {
+	// Iterate the entire index
+	indexed := make(map[string][]byte)
+	err := db.View(func(t database.Transaction) error {
+		return t.IterateTxIDs(func(txID, blockHash []byte) (bool, error) {
+			indexed[string(txID)] = append([]byte{}, blockHash...)
+			return true, nil
+		})
+	})
+	require.NoError(test, err)
+
+	for _, blk := range blocks {
+		for _, tx := range blk.Txs {
+			txID, err := tx.CalculateHash()
+			require.NoError(test, err)
+			require.Equal(test, blk.Header.Hash, indexed[string(txID)])
+		}
+	}
+
+	// Stop iteration earlier
+	var count int
+	err = db.View(func(t database.Transaction) error {
+		return t.IterateTxIDs(func(txID, blockHash []byte) (bool, error) {
+			count++
+			return count < 3, nil
+		})
+	})
+	require.NoError(test, err)
+	require.Equal(test, 3, count)
+
+	// An error is propagated
+	forcedError := errors.New("forced error")
+	err = db.View(func(t database.Transaction) error {
+		return t.IterateTxIDs(func(txID, blockHash []byte) (bool, error) {
+			return true, forcedError
+		})
+	})
+	require.Equal(test, forcedError, err)
+}
+
+func TestFetchTxsByIndices(test *testing.T) {
+	contractID, err := crypto.RandEntropy(32)
+	require.NoError(test, err)
+
+	genBlocks, err := generateChainBlocks(2)
+	require.NoError(test, err)
+
+	callTxIDs := make([][]byte, 0)
+
+	for _, b := range genBlocks {
+		tx, err := contractCallTx(contractID)
+		require.NoError(test, err)
+
+		b.AddTx(tx)
+
+		txID, err := tx.CalculateHash()
+		require.NoError(test, err)
+
+		callTxIDs = append(callTxIDs, txID)
+	}
+
+	require.NoError(test, storeBlocks(db, genBlocks))
+
+	err = db.View(func(t database.Transaction) error {
+		// Each tx can be found by its nullifiers
+		for _, b := range genBlocks {
+			for i, tx := range b.Txs {
+				decoded, err := tx.Decode()
+				if err != nil {
+					return err
+				}
+
+				for _, nullifier := range decoded.Nullifiers {
+					fetched, txIndex, hash, err := t.FetchTxByNullifier(nullifier)
+					if err != nil {
+						return err
+					}
+
+					if !transactions.Equal(tx, fetched) || txIndex != uint32(i) || !bytes.Equal(hash, b.Header.Hash) {
+						return fmt.Errorf("invalid tx fetched by nullifier on height %d", b.Header.Height)
+					}
+				}
+			}
+		}
+
+		// Contract txs are sorted by height, latest first
+		txIDs, err := t.FetchTxsByContract(contractID, len(callTxIDs)+1)
+		if err != nil {
+			return err
+		}
+
+		require.Equal(test, [][]byte{callTxIDs[1], callTxIDs[0]}, txIDs)
+
+		txIDs, err = t.FetchTxsByContract(contractID, 1)
+		if err != nil {
+			return err
+		}
+
+		require.Equal(test, [][]byte{callTxIDs[1]}, txIDs)
+
+		_, _, _, err = t.FetchTxByNullifier(make([]byte, 32))
+		require.Equal(test, database.ErrTxNotFound, err)
+
+		return nil
+	})
+	require.NoError(test, err)
+}
+
+// TestTxIndicesDisabled ensures lookups of disabled secondary indices fail.
+func TestTxIndicesDisabled(test *testing.T) {
+	orig := cfg.Get()
+	defer cfg.Mock(&orig)
+
+	r := cfg.Get()
+	r.Database.IndexNullifiers = false
+	r.Database.IndexContracts = false
+	cfg.Mock(&r)
+
+	dir, err := ioutil.TempDir(os.TempDir(), drvrName+"_unindexed_store_")
+	require.NoError(test, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	udb, err := drvr.Open(dir, false)
+	require.NoError(test, err)
+
+	defer func() {
+		_ = udb.Close()
+	}()
+
+	require.NoError(test, udb.View(func(t database.Transaction) error {
+		_, _, _, err := t.FetchTxByNullifier(make([]byte, 32))
+		require.Equal(test, database.ErrIndexDisabled, err)
+
+		_, err = t.FetchTxsByContract(make([]byte, 32), 1)
+		require.Equal(test, database.ErrIndexDisabled, err)
+		return nil
+	}))
+}
+
+// TestMultipleStorages ensures a driver can open several storages at a time
+// and each of them is isolated from the others.
+func TestMultipleStorages(test *testing.T) {
+	otherDir, err := ioutil.TempDir(os.TempDir(), drvrName+"_other_store_")
+	require.NoError(test, err)
+
+	defer func() {
+		_ = os.RemoveAll(otherDir)
+	}()
+
+	otherDB, err := drvr.Open(otherDir, false)
+	require.NoError(test, err)
+
+	genBlocks, err := generateChainBlocks(2)
+	require.NoError(test, err)
+
+	require.NoError(test, storeBlocks(otherDB, genBlocks))
+
+	// Blocks must be found only in the storage they were stored into
+	for _, b := range genBlocks {
+		require.NoError(test, otherDB.View(func(t database.Transaction) error {
+			_, e := t.FetchBlockExists(b.Header.Hash)
+			return e
+		}))
+
+		require.Error(test, db.View(func(t database.Transaction) error {
+			_, e := t.FetchBlockExists(b.Header.Hash)
+			return e
+		}))
+	}
+
+	// Closing a storage must not affect the others
+	require.NoError(test, otherDB.Close())
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		_, e := t.FetchBlockExists(blocks[0].Header.Hash)
+		return e
+	}))
+}
+
+// TestReadOnlyStorage ensures a storage which is not open yet can be opened
+// in read-only mode.
+func TestReadOnlyStorage(test *testing.T) {
+	if drvrName == lite.DriverName {
+		test.Skip()
+	}
+
+	dir, err := ioutil.TempDir(os.TempDir(), drvrName+"_readonly_store_")
+	require.NoError(test, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	rwDB, err := drvr.Open(dir, false)
+	require.NoError(test, err)
+
+	genBlocks, err := generateChainBlocks(2)
+	require.NoError(test, err)
+
+	require.NoError(test, storeBlocks(rwDB, genBlocks))
+	require.NoError(test, rwDB.Close())
+
+	roDB, err := drvr.Open(dir, true)
+	require.NoError(test, err)
+
+	defer func() {
+		_ = roDB.Close()
+	}()
+
+	for _, b := range genBlocks {
+		require.NoError(test, roDB.View(func(t database.Transaction) error {
+			_, e := t.FetchBlockExists(b.Header.Hash)
+			return e
+		}))
+	}
+
+	require.Error(test, storeBlocks(roDB, genBlocks))
+}
+
+// TestCandidateMessages ensures candidate blocks are listed and deleted by
+// round.
+func TestCandidateMessages(test *testing.T) {
+	cms := []*block.Block{
+		helper.RandomBlock(1, 1),
+		helper.RandomBlock(2, 1),
+		helper.RandomBlock(2, 1),
+		helper.RandomBlock(3, 1),
+	}
+
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		for _, cm := range cms {
+			if err := t.StoreCandidateMessage(*cm); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		round, err := t.FetchCandidateMessagesByRound(2)
+		require.NoError(test, err)
+		require.Len(test, round, 2)
+
+		for _, cm := range round {
+			require.Equal(test, uint64(2), cm.Header.Height)
+		}
+
+		return nil
+	}))
+
+	// Delete candidates up to round 2
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.DeleteCandidateMessages(2)
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		for _, cm := range cms[:3] {
+			_, err := t.FetchCandidateMessage(cm.Header.Hash)
+			require.Equal(test, database.ErrBlockNotFound, err)
+		}
+
+		round, err := t.FetchCandidateMessagesByRound(2)
+		require.NoError(test, err)
+		require.Empty(test, round)
+
+		_, err = t.FetchCandidateMessage(cms[3].Header.Hash)
+		return err
+	}))
+
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.ClearCandidateMessages()
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		round, err := t.FetchCandidateMessagesByRound(3)
+		require.NoError(test, err)
+		require.Empty(test, round)
+		return nil
+	}))
+}
+
+func TestJournal(test *testing.T) {
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		_, err := t.FetchJournal()
+		require.Equal(test, database.ErrJournalEmpty, err)
+		return nil
+	}))
+
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.StoreJournal(blocks[0])
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		b, err := t.FetchJournal()
+		require.NoError(test, err)
+		require.True(test, b.Equals(blocks[0]))
+		return nil
+	}))
+
+	require.NoError(test, db.Update(func(t database.Transaction) error {
+		return t.DeleteJournal()
+	}))
+
+	require.NoError(test, db.View(func(t database.Transaction) error {
+		_, err := t.FetchJournal()
+		require.Equal(test, database.ErrJournalEmpty, err)
+		return nil
+	}))
+}
+
+// TestSnapshotArchive ensures an archived snapshot can be opened as a storage
+// holding all records stored prior to the snapshot.
+func TestSnapshotArchive(test *testing.T) {
+	s, ok := db.(database.Snapshotter)
+	if !ok {
+		test.Skip("online backups not supported")
+	}
+
+	snapshot, err := s.Snapshot()
+	require.NoError(test, err)
+
+	// Records stored after the snapshot must not be archived
+	genBlocks, err := generateChainBlocks(1)
+	require.NoError(test, err)
+	require.NoError(test, storeBlocks(db, genBlocks))
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	require.NoError(test, snapshot.Archive(tw, "chain"))
+	require.NoError(test, tw.Close())
+	snapshot.Close()
+
+	restoreDir, err := ioutil.TempDir(os.TempDir(), drvrName+"_restored_store_")
+	require.NoError(test, err)
+
+	defer func() {
+		_ = os.RemoveAll(restoreDir)
+	}()
+
+	tr := tar.NewReader(buf)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(test, err)
+
+		name := filepath.Join(restoreDir, filepath.FromSlash(hdr.Name))
+		require.NoError(test, os.MkdirAll(filepath.Dir(name), 0700))
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(test, err)
+		require.NoError(test, ioutil.WriteFile(name, data, 0600))
+	}
+
+	restoredDB, err := drvr.Open(filepath.Join(restoreDir, "chain"), true)
+	require.NoError(test, err)
+
+	defer func() {
+		_ = restoredDB.Close()
+	}()
+
+	require.NoError(test, restoredDB.View(func(t database.Transaction) error {
+		for _, b := range blocks {
+			if _, e := t.FetchBlock(b.Header.Hash); e != nil {
+				return e
+			}
+		}
+
+		_, e := t.FetchBlockExists(genBlocks[0].Header.Hash)
+		require.Equal(test, database.ErrBlockNotFound, e)
+		return nil
+	}))
+}
+
+// TestPruning ensures transactions of blocks older than the prune depth are
+// deleted once a block is persisted, while their headers are kept.
+func TestPruning(test *testing.T) {
+	if drvrName != heavy.DriverName {
+		test.Skip("pruning mode not supported")
+	}
+
+	orig := cfg.Get()
+	defer cfg.Mock(&orig)
+
+	r := cfg.Get()
+	r.Database.PruneDepth = 3
+	cfg.Mock(&r)
+
+	dir, err := ioutil.TempDir(os.TempDir(), drvrName+"_pruned_store_")
+	require.NoError(test, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	pdb, err := drvr.Open(dir, false)
+	require.NoError(test, err)
+
+	defer func() {
+		_ = pdb.Close()
+	}()
+
+	chain := make([]*block.Block, 10)
+	for h := range chain {
+		chain[h] = helper.RandomBlock(uint64(h), 1)
+	}
+
+	// Nothing is pruned until a block is persisted
+	require.NoError(test, storeBlocks(pdb, chain[:8]))
+	require.NoError(test, pdb.View(func(t database.Transaction) error {
+		_, err := t.FetchBlockTxs(chain[0].Header.Hash)
+		return err
+	}))
+
+	// Blocks up to height 5 are pruned once block 8 is persisted
+	require.NoError(test, pdb.Update(func(t database.Transaction) error {
+		return t.StoreBlock(chain[8], true)
+	}))
+
+	require.NoError(test, pdb.View(func(t database.Transaction) error {
+		r, err := t.FetchRegistry()
+		require.NoError(test, err)
+		require.True(test, r.Pruned)
+		require.Equal(test, uint64(5), r.PrunedHeight)
+
+		// The genesis block is kept in full
+		txs, err := t.FetchBlockTxs(chain[0].Header.Hash)
+		require.NoError(test, err)
+		require.Len(test, txs, len(chain[0].Txs))
+
+		for _, b := range chain[1:6] {
+			// Headers and certificates are kept
+			header, err := t.FetchBlockHeader(b.Header.Hash)
+			require.NoError(test, err)
+			require.Equal(test, b.Header.Certificate, header.Certificate)
+
+			hash, err := t.FetchBlockHashByHeight(b.Header.Height)
+			require.NoError(test, err)
+			require.Equal(test, b.Header.Hash, hash)
+
+			exists, err := t.FetchBlockExists(b.Header.Hash)
+			require.NoError(test, err)
+			require.True(test, exists)
+
+			// Transactions lookups report the pruning
+			_, err = t.FetchBlockTxs(b.Header.Hash)
+			require.Equal(test, database.ErrBlockPruned, err)
+
+			txID, err := b.Txs[0].CalculateHash()
+			require.NoError(test, err)
+
+			_, _, _, err = t.FetchBlockTxByHash(txID)
+			require.Equal(test, database.ErrBlockPruned, err)
+		}
+
+		for _, b := range chain[6:9] {
+			txs, err := t.FetchBlockTxs(b.Header.Hash)
+			require.NoError(test, err)
+			require.Len(test, txs, len(b.Txs))
+		}
+
+		return nil
+	}))
+
+	// Pruning resumes from the latest pruned block
+	require.NoError(test, pdb.Update(func(t database.Transaction) error {
+		return t.StoreBlock(chain[9], true)
+	}))
+
+	require.NoError(test, pdb.View(func(t database.Transaction) error {
+		r, err := t.FetchRegistry()
+		require.NoError(test, err)
+		require.Equal(test, uint64(6), r.PrunedHeight)
+
+		_, err = t.FetchBlockTxs(chain[6].Header.Hash)
+		require.Equal(test, database.ErrBlockPruned, err)
+
+		_, err = t.FetchBlockTxs(chain[7].Header.Hash)
+		require.NoError(test, err)
+		return nil
+	}))
+}
+
 func TestClearDatabase(test *testing.T) {
 	err := db.Update(func(t database.Transaction) error {
 		return t.ClearDatabase()
@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 )
@@ -75,3 +76,35 @@ func generateRandomBlocks(blocksCount int) []*block.Block {
 	}
 	return newBlocks
 }
+
+// contractCallTx returns a random tx calling the contract contractID.
+func contractCallTx(contractID []byte) (*transactions.Transaction, error) {
+	tx := transactions.RandTx()
+
+	// Replace the call flag of the random tx payload (trailing 8 bytes) with a
+	// call.
+	data := tx.Payload.Data
+	data = append(data[:len(data)-8:len(data)-8], 1, 0, 0, 0, 0, 0, 0, 0)
+	data = append(data, contractID...)
+	data = append(data, 0x01)
+
+	tx.Payload.Data = data
+
+	decoded, err := tx.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if decoded.Call == nil {
+		return nil, fmt.Errorf("tx does not call a contract")
+	}
+
+	hash, err := decoded.Hash(tx.TxType)
+	if err != nil {
+		return nil, err
+	}
+
+	copy(tx.Hash[:], hash)
+
+	return tx, nil
+}
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"errors"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -19,9 +20,10 @@ import (
 )
 
 const (
-	feePrefix       = "fi:"
-	feeIndex        = "fee_index"
-	itemsCountPerTx = 2
+	feePrefix  = "fi:"
+	feeIndex   = "fee_index"
+	ratePrefix = "fr:"
+	rateIndex  = "rate_index"
 )
 
 const (
@@ -37,11 +39,14 @@ type (
 
 		// Cumulative size of all added transactions
 		cumulativeTxsSize uint32
+		// Number of added transactions
+		txsCount uint32
 	}
 )
 
 // newBuntdbPool opens/creates buntdb file with buntdb.EverySecond config.
-// fee_index is created to sort transaction ids by fee.
+// fee_index is created to sort transaction ids by fee, and rate_index to sort
+// them by fee rate. Fee rates missing from an older pool are added.
 func (m *buntdbPool) Create(path string) error {
 	db, errOpen := buntdb.Open(path)
 	if errOpen != nil {
@@ -72,10 +77,20 @@ func (m *buntdbPool) Create(path string) error {
 		log.WithError(err).Warn("could not create indices")
 	}
 
+	if err := m.addMissingRates(); err != nil {
+		log.WithError(err).Warn("could not add fee rates")
+	}
+
 	// Sum cumulative trasactions size
 	cumulativeTxsSize := uint32(0)
+	txsCount := uint32(0)
+
 	_ = db.View(func(tx *buntdb.Tx) error {
 		err := tx.Ascend("", func(key, value string) bool {
+			if !isTxKey(key) {
+				return true
+			}
+
 			buf := bytes.NewBufferString(value)
 
 			txd, err := unmarshalTxDesc(buf, needTxSizeOnly)
@@ -84,18 +99,60 @@ func (m *buntdbPool) Create(path string) error {
 			}
 
 			cumulativeTxsSize += uint32(txd.size)
+			txsCount++
 			return true // continue iteration
 		})
 		return err
 	})
 
 	atomic.StoreUint32(&m.cumulativeTxsSize, cumulativeTxsSize)
+	atomic.StoreUint32(&m.txsCount, txsCount)
 	return nil
 }
 
-// Put adds new transaction to the pool. It creates two key-value pairs per a tx.
+// addMissingRates sets the fee rate of the transactions stored by a release
+// prior to fee rate eviction, so that they are indexed by rate_index too.
+func (m *buntdbPool) addMissingRates() error {
+	return m.db.Update(func(tx *buntdb.Tx) error {
+		rates := make(map[string]string)
+
+		err := tx.Ascend("", func(key, value string) bool {
+			if !isTxKey(key) {
+				return true
+			}
+
+			if _, err := tx.Get(ratePrefix + key); err != buntdb.ErrNotFound {
+				return true
+			}
+
+			txd, err := unmarshalTxDesc(bytes.NewBufferString(value), needFullTx)
+			if err != nil {
+				log.WithError(err).Warn("could not read transaction")
+				return true
+			}
+
+			rates[ratePrefix+key] = strconv.FormatFloat(txd.feeRate(), 'g', -1, 64)
+			return true // continue iteration
+		})
+		if err != nil {
+			return err
+		}
+
+		// Keys can not be set while iterating.
+		for key, rate := range rates {
+			if _, _, err = tx.Set(key, rate, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Put adds new transaction to the pool. It creates three key-value pairs per a tx.
 // transacton_id -> transaction data (marshaled)
 // fi:transacton_id -> transaction fee value
+// fr:transacton_id -> transaction fee rate value
 func (m *buntdbPool) Put(t TxDesc) error {
 	var key, value bytes.Buffer
 	var err error
@@ -130,7 +187,15 @@ func (m *buntdbPool) Put(t TxDesc) error {
 			return err
 		}
 
+		rateKey := ratePrefix + key.String()
+
+		_, _, err = tx.Set(rateKey, strconv.FormatFloat(t.feeRate(), 'g', -1, 64), nil)
+		if err != nil {
+			return err
+		}
+
 		atomic.AddUint32(&m.cumulativeTxsSize, uint32(t.size))
+		atomic.AddUint32(&m.txsCount, 1)
 		return nil
 	})
 
@@ -226,9 +291,16 @@ func (m *buntdbPool) Delete(txID []byte) error {
 			return err
 		}
 
+		// The fee rate may be missing, see addMissingRates
+		_, err = t.Delete(ratePrefix + key.String())
+		if err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+
 		// subtract deleted tx size
 		atomic.AddUint32(&m.cumulativeTxsSize, ^uint32(size-1))
-		return err
+		atomic.AddUint32(&m.txsCount, ^uint32(0))
+		return nil
 	})
 
 	return err
@@ -238,6 +310,10 @@ func (m *buntdbPool) Delete(txID []byte) error {
 func (m *buntdbPool) Range(fn func(k txHash, t TxDesc) error) error {
 	err := m.db.View(func(tx *buntdb.Tx) error {
 		err := tx.Ascend("", func(key, value string) bool {
+			if !isTxKey(key) {
+				return true
+			}
+
 			buf := bytes.NewBufferString(value)
 
 			txdesc, err := unmarshalTxDesc(buf, needFullTx)
@@ -261,6 +337,90 @@ func (m *buntdbPool) Range(fn func(k txHash, t TxDesc) error) error {
 	return err
 }
 
+// Evict implements Pool.Evict.
+func (m *buntdbPool) Evict(size uint32, feeRate float64) ([]TxDesc, error) {
+	evicted := make([]TxDesc, 0)
+
+	err := m.db.Update(func(tx *buntdb.Tx) error {
+		var (
+			released uint32
+			txids    []string
+			err      error
+		)
+
+		// Iterate keys sorted by fee rate, cheapest first.
+		iterErr := tx.Ascend(rateIndex, func(rateKey, rate string) bool {
+			if released >= size {
+				return false
+			}
+
+			var r float64
+
+			r, err = strconv.ParseFloat(rate, 64)
+			if err != nil {
+				return false
+			}
+
+			if r >= feeRate {
+				err = errNoRoom
+				return false
+			}
+
+			txid := rateKey[len(ratePrefix):]
+
+			var value string
+
+			value, err = tx.Get(txid)
+			if err != nil {
+				return false
+			}
+
+			var txdesc TxDesc
+
+			txdesc, err = unmarshalTxDesc(bytes.NewBufferString(value), needFullTx)
+			if err != nil {
+				return false
+			}
+
+			released += uint32(txdesc.size)
+			txids = append(txids, txid)
+			evicted = append(evicted, txdesc)
+
+			return true // continue iteration
+		})
+		if iterErr != nil {
+			return iterErr
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if released < size {
+			return errNoRoom
+		}
+
+		// Keys can not be deleted while iterating.
+		for _, txid := range txids {
+			for _, key := range []string{txid, feePrefix + txid, ratePrefix + txid} {
+				if _, err = tx.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		// subtract evicted txs size
+		atomic.AddUint32(&m.cumulativeTxsSize, ^uint32(released-1))
+		atomic.AddUint32(&m.txsCount, ^uint32(len(txids)-1))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evicted, nil
+}
+
 // Size of the txs.
 func (m *buntdbPool) Size() uint32 {
 	return atomic.LoadUint32(&m.cumulativeTxsSize)
@@ -268,14 +428,7 @@ func (m *buntdbPool) Size() uint32 {
 
 // Len returns the number of tx entries.
 func (m *buntdbPool) Len() int {
-	var len int
-
-	_ = m.db.View(func(tx *buntdb.Tx) error {
-		len, _ = tx.Len()
-		return nil
-	})
-
-	return len / itemsCountPerTx
+	return int(atomic.LoadUint32(&m.txsCount))
 }
 
 // RangeSort iterates through all tx entries sorted by Fee
@@ -322,6 +475,10 @@ func (m *buntdbPool) ContainAnyNullifiers(nullifiers [][]byte) (bool, []byte) {
 
 	_ = m.db.View(func(tx *buntdb.Tx) error {
 		_ = tx.Ascend("", func(key, value string) bool {
+			if !isTxKey(key) {
+				return true
+			}
+
 			t, err := unmarshalTxDesc(bytes.NewBufferString(value), needFullTx)
 			if err != nil {
 				return true
@@ -370,6 +527,10 @@ func (m *buntdbPool) GetTxsByNullifier(nullifier []byte) ([][]byte, error) {
 
 	err := m.db.View(func(tx *buntdb.Tx) error {
 		err := tx.Ascend("", func(key, value string) bool {
+			if !isTxKey(key) {
+				return true
+			}
+
 			buf := bytes.NewBufferString(value)
 
 			txdesc, err := unmarshalTxDesc(buf, needFullTx)
@@ -407,23 +568,18 @@ func (m *buntdbPool) GetTxsByNullifier(nullifier []byte) ([][]byte, error) {
 }
 
 func (m *buntdbPool) createIndices() error {
-	var found bool
-	// Create index for sorting txids by fee
+	// Create indices for sorting txids by fee and by fee rate
 	indexList, err := m.db.Indexes()
 	if err != nil {
 		return err
 	}
 
+	found := make(map[string]bool, len(indexList))
 	for _, name := range indexList {
-		if name != feeIndex {
-			continue
-		}
-
-		found = true
-		break
+		found[name] = true
 	}
 
-	if !found {
+	if !found[feeIndex] {
 		pattern := feePrefix + "*"
 		// An error will occur if an index with the same name already exists.
 		if err := m.db.CreateIndex(feeIndex, pattern, buntdb.IndexInt); err != nil {
@@ -431,9 +587,21 @@ func (m *buntdbPool) createIndices() error {
 		}
 	}
 
+	if !found[rateIndex] {
+		pattern := ratePrefix + "*"
+		if err := m.db.CreateIndex(rateIndex, pattern, buntdb.IndexFloat); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// isTxKey returns false for the keys of the fee and fee rate indices.
+func isTxKey(key string) bool {
+	return !strings.HasPrefix(key, feePrefix) && !strings.HasPrefix(key, ratePrefix)
+}
+
 func (m buntdbPool) Close() {
 	if err := m.db.Close(); err != nil {
 		log.WithError(err).Warn("buntdb close with error")
@@ -17,6 +17,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	crypto "github.com/dusk-network/dusk-crypto/hash"
 	assert "github.com/stretchr/testify/require"
+	"github.com/tidwall/buntdb"
 )
 
 func createTemp(pattern string) string {
@@ -111,3 +112,75 @@ func TestBuntSortedKeys(tst *testing.T) {
 
 	assert.NotEqual(prevVal != math.MaxUint64, "rangesort not called")
 }
+
+func TestBuntEvict(t *testing.T) {
+	dbpath := createTemp("file3.db")
+	defer os.Remove(dbpath)
+
+	pool := buntdbPool{}
+	pool.Create(dbpath)
+
+	testEvict(t, &pool)
+}
+
+// TestBuntMissingRates ensures a pool stored by a release prior to fee rate
+// eviction, without fee rates, is still usable.
+func TestBuntMissingRates(t *testing.T) {
+	assert := assert.New(t)
+
+	dbpath := createTemp("file4.db")
+	defer os.Remove(dbpath)
+
+	pool := buntdbPool{}
+	assert.NoError(pool.Create(dbpath))
+
+	hashes := make([][]byte, 0)
+
+	for i := 0; i < 3; i++ {
+		td := TxDesc{tx: transactions.MockTxWithParams(transactions.Transfer, 0)}
+		assert.NoError(pool.Put(td))
+
+		hash, err := td.tx.CalculateHash()
+		assert.NoError(err)
+
+		hashes = append(hashes, hash)
+	}
+
+	dropRates := func() {
+		assert.NoError(pool.db.Update(func(tx *buntdb.Tx) error {
+			for _, hash := range hashes {
+				if _, err := tx.Delete(ratePrefix + string(hash)); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}))
+	}
+
+	// A transaction without fee rate can be deleted
+	dropRates()
+	assert.NoError(pool.Delete(hashes[0]))
+	assert.False(pool.Contain(hashes[0]))
+	assert.Equal(2, pool.Len())
+
+	hashes = hashes[1:]
+	pool.Close()
+
+	// Missing fee rates are added once the pool is loaded
+	pool = buntdbPool{}
+	assert.NoError(pool.Create(dbpath))
+	assert.Equal(2, pool.Len())
+
+	assert.NoError(pool.db.View(func(tx *buntdb.Tx) error {
+		for _, hash := range hashes {
+			if _, err := tx.Get(ratePrefix + string(hash)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	pool.Close()
+}
@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package mempool
+
+import (
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message/payload"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+)
+
+// EventType is the type of change of the mempool state.
+type EventType uint8
+
+const (
+	// Replacement of pooled txs by a tx spending the same nullifiers with a
+	// higher fee.
+	Replacement EventType = iota
+	// Evicted tx, removed from the mempool before being included in a block.
+	Evicted
+	// Expired tx, removed from the mempool after its time to live or max
+	// inclusion attempts.
+	Expired
+	// Accepted tx, added to the mempool.
+	Accepted
+	// Rejected tx, not added to the mempool.
+	Rejected
+	// Included tx, removed from the mempool as included in an accepted block.
+	Included
+)
+
+var eventTypeNames = [...]string{"replacement", "evicted", "expired", "accepted", "rejected", "included"}
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	if int(t) < len(eventTypeNames) {
+		return eventTypeNames[t]
+	}
+
+	return "unknown"
+}
+
+// Event is a change of the mempool state. It is the payload of
+// topics.MempoolEvent events.
+type Event struct {
+	Type EventType
+	TxID []byte
+
+	// Replaced txs of a Replacement.
+	Replaced [][]byte
+	// Reason of an eviction, an expiry or a rejection.
+	Reason string
+	// Height of the block including the tx.
+	Height uint64
+}
+
+// Copy an Event.
+// Implements the payload.Safe interface.
+func (e Event) Copy() payload.Safe {
+	c := Event{
+		Type:     e.Type,
+		TxID:     make([]byte, len(e.TxID)),
+		Replaced: make([][]byte, len(e.Replaced)),
+		Reason:   e.Reason,
+		Height:   e.Height,
+	}
+
+	copy(c.TxID, e.TxID)
+
+	for i, txid := range e.Replaced {
+		c.Replaced[i] = make([]byte, len(txid))
+		copy(c.Replaced[i], txid)
+	}
+
+	return c
+}
+
+func publishEvent(publisher eventbus.Publisher, e Event) {
+	publisher.Publish(topics.MempoolEvent, message.New(topics.MempoolEvent, e))
+}
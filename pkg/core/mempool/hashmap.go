@@ -9,10 +9,12 @@ package mempool
 import (
 	"bytes"
 	"errors"
+	"os"
 	"sort"
 	"sync"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 )
 
 type (
@@ -21,6 +23,11 @@ type (
 		f uint64
 	}
 
+	keyRate struct {
+		k txHash
+		r float64
+	}
+
 	// HashMap represents a pool implementation based on golang map. The generic
 	// solution to bench against.
 	HashMap struct {
@@ -33,21 +40,101 @@ type (
 		// Block Generator to fetch highest-fee txs without delays in sorting.
 		sorted []keyFee
 
+		// byRate is data keys sorted by fee rate in an ascending order. It
+		// allows to evict the cheapest txs first.
+		byRate []keyRate
+
 		// spent key images from the transactions in the pool
 		// spentkeyImages map[keyImage]bool.
 		Capacity uint32
 		txsSize  uint32
+
+		// path of the file the txs are dumped into on Close.
+		path string
 	}
 )
 
-// Create instantiates hashmap and sort index.
+// Create instantiates hashmap and sort index. If a path is provided, the txs
+// dumped into it on Close are reloaded.
 func (m *HashMap) Create(path string) error {
 	m.data = make(map[txHash]TxDesc, m.Capacity)
 	m.sorted = make([]keyFee, 0, m.Capacity)
+	m.byRate = make([]keyRate, 0, m.Capacity)
+	m.path = path
+
+	if len(path) == 0 {
+		return nil
+	}
+
+	// A corrupted dump should not prevent the node from starting
+	if err := m.load(); err != nil {
+		log.WithError(err).WithField("path", path).Warn("could not reload mempool dump")
+	}
+
+	return nil
+}
+
+func (m *HashMap) load() error {
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(data)
+
+	for buf.Len() > 0 {
+		var value []byte
+		if err := encoding.ReadVarBytes(buf, &value); err != nil {
+			return err
+		}
+
+		t, err := unmarshalTxDesc(bytes.NewBuffer(value), needFullTx)
+		if err != nil {
+			return err
+		}
+
+		if err := m.Put(t); err != nil && !errors.Is(err, ErrAlreadyExists) {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// dump writes all txs into the file at path, sorted by Fee so that the order
+// of txs with the same fee is kept on reload.
+func (m *HashMap) dump() error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	buf := new(bytes.Buffer)
+
+	for _, entry := range m.sorted {
+		t := m.data[entry.k]
+
+		var value bytes.Buffer
+		if err := marshalTxDesc(&value, &t); err != nil {
+			return err
+		}
+
+		if err := encoding.WriteVarBytes(buf, value.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	// Write to a temporary file first, not to lose a previous dump on failure
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, m.path)
+}
+
 // Put sets the value for the given key. It overwrites any previous value
 // for that key.
 func (m *HashMap) Put(t TxDesc) error {
@@ -92,6 +179,19 @@ func (m *HashMap) Put(t TxDesc) error {
 	copy(m.sorted[index+1:], m.sorted[index:])
 
 	m.sorted[index] = keyFee{k: k, f: fee}
+
+	// sort keys by fee rate. Among the same fee rate, older txs come first.
+	rate := t.feeRate()
+
+	index = sort.Search(len(m.byRate), func(i int) bool {
+		return m.byRate[i].r > rate
+	})
+
+	m.byRate = append(m.byRate, keyRate{})
+
+	copy(m.byRate[index+1:], m.byRate[index:])
+
+	m.byRate[index] = keyRate{k: k, r: rate}
 	return nil
 }
 
@@ -167,6 +267,10 @@ func (m *HashMap) Delete(txID []byte) error {
 
 	copy(k[:], txID)
 
+	return m.delete(k)
+}
+
+func (m *HashMap) delete(k txHash) error {
 	tx, ok := m.data[k]
 	if !ok {
 		return errNotFound
@@ -180,12 +284,62 @@ func (m *HashMap) Delete(txID []byte) error {
 	for i, entry := range m.sorted {
 		if entry.k == k {
 			m.sorted = append(m.sorted[:i], m.sorted[i+1:]...)
+			break
+		}
+	}
+
+	for i, entry := range m.byRate {
+		if entry.k == k {
+			m.byRate = append(m.byRate[:i], m.byRate[i+1:]...)
+			break
 		}
 	}
 
 	return nil
 }
 
+// Evict implements Pool.Evict.
+func (m *HashMap) Evict(size uint32, feeRate float64) ([]TxDesc, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var (
+		released uint32
+		count    int
+	)
+
+	for _, entry := range m.byRate {
+		if released >= size {
+			break
+		}
+
+		if entry.r >= feeRate {
+			return nil, errNoRoom
+		}
+
+		released += uint32(m.data[entry.k].size)
+		count++
+	}
+
+	if released < size {
+		return nil, errNoRoom
+	}
+
+	keys := make([]txHash, count)
+	for i := range keys {
+		keys[i] = m.byRate[i].k
+	}
+
+	evicted := make([]TxDesc, 0, count)
+
+	for _, k := range keys {
+		evicted = append(evicted, m.data[k])
+		_ = m.delete(k)
+	}
+
+	return evicted, nil
+}
+
 // Size of the txs.
 func (m *HashMap) Size() uint32 {
 	m.lock.RLock()
@@ -282,7 +436,9 @@ func (m *HashMap) GetTxsByNullifier(nullifier []byte) ([][]byte, error) {
 
 		for _, n := range d.Nullifiers {
 			if bytes.Equal(n, nullifier) {
-				found = append(found, k[:])
+				// k is reused by the next iteration
+				txid := k
+				found = append(found, txid[:])
 				break
 			}
 		}
@@ -295,6 +451,13 @@ func (m *HashMap) GetTxsByNullifier(nullifier []byte) ([][]byte, error) {
 	return found, nil
 }
 
-// Close empty implementation of Pool.Close.
+// Close dumps the txs into the file the pool was created with, if any.
 func (m *HashMap) Close() {
+	if len(m.path) == 0 {
+		return
+	}
+
+	if err := m.dump(); err != nil {
+		log.WithError(err).WithField("path", m.path).Warn("could not dump mempool")
+	}
 }
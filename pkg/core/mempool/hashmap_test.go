@@ -10,6 +10,8 @@ import (
 	"errors"
 	"math"
 	"math/rand"
+	"os"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -110,6 +112,96 @@ func TestGet(t *testing.T) {
 	assert.Nil(pool.Get(hash))
 }
 
+func TestEvict(t *testing.T) {
+	pool := &HashMap{lock: &sync.RWMutex{}, Capacity: 10}
+	pool.Create("")
+
+	testEvict(t, pool)
+}
+
+// testEvict ensures the pool evicts the txs with the lowest fee rate first.
+func testEvict(t *testing.T, pool Pool) {
+	assert := assert.New(t)
+
+	// Generate 10 random txs of 100 bytes
+	rates := make([]float64, 0, 10)
+
+	for i := 0; i < 10; i++ {
+		td := TxDesc{
+			tx:       transactions.MockTxWithParams(transactions.Transfer, 0),
+			received: time.Now(),
+			size:     100,
+		}
+
+		assert.NoError(pool.Put(td))
+		rates = append(rates, td.feeRate())
+	}
+
+	sort.Float64s(rates)
+
+	// Cheaper txs can not be evicted
+	_, err := pool.Evict(250, rates[2])
+	assert.Error(err)
+	assert.Equal(10, pool.Len())
+	assert.Equal(uint32(1000), pool.Size())
+
+	// The 3 cheapest txs are evicted to release 250 bytes
+	evicted, err := pool.Evict(250, rates[9]+1)
+	assert.NoError(err)
+	assert.Len(evicted, 3)
+	assert.Equal(7, pool.Len())
+	assert.Equal(uint32(700), pool.Size())
+
+	for i, td := range evicted {
+		assert.Equal(rates[i], td.feeRate())
+
+		txid, _ := td.tx.CalculateHash()
+		assert.False(pool.Contain(txid))
+	}
+
+	// Not enough room can be made
+	_, err = pool.Evict(800, rates[9]+1)
+	assert.Error(err)
+	assert.Equal(7, pool.Len())
+}
+
+func TestDumpReload(t *testing.T) {
+	assert := assert.New(t)
+
+	path := createTemp("mempool.dump")
+	defer os.Remove(path)
+
+	pool := &HashMap{lock: &sync.RWMutex{}, Capacity: 10}
+	assert.NoError(pool.Create(path))
+
+	txs := transactions.RandContractCalls(10, 0, false)
+	for i, tx := range txs {
+		assert.NoError(pool.Put(TxDesc{tx: tx, received: time.Now(), size: uint(i)}))
+	}
+
+	pool.Close()
+
+	reloaded := &HashMap{lock: &sync.RWMutex{}, Capacity: 10}
+	assert.NoError(reloaded.Create(path))
+
+	assert.Equal(pool.Len(), reloaded.Len())
+	assert.Equal(pool.Size(), reloaded.Size())
+
+	// Txs are reloaded in the same order
+	expected := make([]txHash, 0, len(txs))
+	_ = pool.RangeSort(func(k txHash, t TxDesc) (bool, error) {
+		expected = append(expected, k)
+		return false, nil
+	})
+
+	i := 0
+	_ = reloaded.RangeSort(func(k txHash, t TxDesc) (bool, error) {
+		assert.Equal(expected[i], k)
+		i++
+		return false, nil
+	})
+}
+
 func BenchmarkPut(b *testing.B) {
 	txs := transactions.RandContractCalls(50000, 0, false)
 
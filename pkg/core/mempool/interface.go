@@ -13,7 +13,10 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 )
 
-var errNotFound = errors.New("not found")
+var (
+	errNotFound = errors.New("not found")
+	errNoRoom   = errors.New("no cheaper transactions to evict")
+)
 
 type txHash [32]byte
 
@@ -31,6 +34,20 @@ type TxDesc struct {
 	kadHeight byte
 }
 
+// feeRate returns the fee paid per byte by the transaction.
+func (t TxDesc) feeRate() float64 {
+	fee, err := t.tx.Fee()
+	if err != nil {
+		return 0
+	}
+
+	if t.size == 0 {
+		return float64(fee)
+	}
+
+	return float64(fee) / float64(t.size)
+}
+
 // Pool represents a transaction pool of the verified txs only.
 type Pool interface {
 	// Create instantiates the underlying data storage.
@@ -71,6 +88,11 @@ type Pool interface {
 	// in a descending order.
 	RangeSort(fn func(k txHash, t TxDesc) (bool, error)) error
 
+	// Evict deletes the transactions with the lowest fee rate until at least
+	// size bytes are released, provided they all pay a lower fee rate than
+	// the given one. Otherwise, nothing is deleted and errNoRoom is returned.
+	Evict(size uint32, feeRate float64) ([]TxDesc, error)
+
 	// Close closes backend.
 	Close()
 }
@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -23,12 +24,15 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	logger "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var log = logger.WithFields(logger.Fields{"process": "mempool"})
@@ -37,6 +41,8 @@ const (
 	idleTime        = 20 * time.Second
 	backendHashmap  = "hashmap"
 	backendDiskpool = "diskpool"
+
+	defaultReplaceByFeeMargin = 10
 )
 
 var (
@@ -46,22 +52,56 @@ var (
 	ErrAlreadyExistsInBlockchain = errors.New("already exists in blockchain")
 	// ErrNullifierExists nullifier(s) already exists in the mempool state.
 	ErrNullifierExists = errors.New("nullifier(s) already exists in the mempool")
+	// ErrInvalidTx transaction rejected by the verifier.
+	ErrInvalidTx = errors.New("invalid transaction")
+	// ErrMempoolFull no room can be made for the transaction.
+	ErrMempoolFull = errors.New("mempool is full")
+	// ErrFeeTooLow transaction fee rate is below the mempool minimum fee rate.
+	ErrFeeTooLow = errors.New("fee rate below the mempool minimum")
+	// ErrReplacementUnderpriced transaction spends the nullifiers of pooled
+	// transactions without raising their gas price by the replace-by-fee margin.
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
 )
 
+// View summarizes the mempool state. It is the response to
+// topics.GetMempoolView requests.
+type View struct {
+	TxsCount int
+	Size     uint32
+	MaxSize  uint32
+	// MinFeeRate is the fee per byte a transaction must pay to be accepted.
+	MinFeeRate float64
+}
+
 // Mempool is a storage for the chain transactions that are valid according to the
 // current chain state and can be included in the next block.
 type Mempool struct {
 	getMempoolTxsChan       <-chan rpcbus.Request
 	getMempoolTxsBySizeChan <-chan rpcbus.Request
+	getMempoolViewChan      <-chan rpcbus.Request
 	sendTxChan              <-chan rpcbus.Request
 
 	// verified txs to be included in next block.
 	verified Pool
 
+	// minFeeRate is raised to the fee rate of the evicted txs, so that cheaper
+	// txs are rejected upfront while the mempool is full. It decays with each
+	// accepted block.
+	feeLock    *sync.RWMutex
+	minFeeRate float64
+
+	// txs are expired once older than ttl, or once dropped maxAttempts times
+	// from a candidate block. attempts is only accessed by the main loop.
+	ttl         time.Duration
+	maxAttempts uint32
+	attempts    map[txHash]uint32
+
 	pendingPropagation chan TxDesc
 
 	// the collector to listen for new accepted blocks.
 	acceptedBlockChan <-chan block.Block
+	// droppedTxsChan receives the txs dropped by Rusk from candidate blocks.
+	droppedTxsChan <-chan message.Message
 
 	eventBus *eventbus.EventBus
 
@@ -71,6 +111,9 @@ type Mempool struct {
 	limiter *rate.Limiter
 
 	db database.DB
+
+	// closed is closed once the main loop is terminated and the pool closed.
+	closed chan struct{}
 }
 
 // NewMempool instantiates and initializes node mempool.
@@ -90,6 +133,11 @@ func NewMempool(db database.DB, eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCB
 		log.WithError(err).Error("failed to register topics.GetMempoolTxsBySize")
 	}
 
+	getMempoolViewChan := make(chan rpcbus.Request, 1)
+	if err := rpcBus.Register(topics.GetMempoolView, getMempoolViewChan); err != nil {
+		log.WithError(err).Error("failed to register topics.GetMempoolView")
+	}
+
 	sendTxChan := make(chan rpcbus.Request, 1)
 	if err := rpcBus.Register(topics.SendMempoolTx, sendTxChan); err != nil {
 		log.WithError(err).Error("failed to register topics.SendMempoolTx")
@@ -97,6 +145,9 @@ func NewMempool(db database.DB, eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCB
 
 	acceptedBlockChan, _ := consensus.InitAcceptedBlockUpdate(eventBus)
 
+	droppedTxsChan := make(chan message.Message, 10)
+	eventBus.Subscribe(topics.DroppedTxs, eventbus.NewChanListener(droppedTxsChan))
+
 	// Enable rate limiter from config
 	cfg := config.Get().Mempool
 
@@ -119,16 +170,36 @@ func NewMempool(db database.DB, eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCB
 			WithField("propagate_burst", burst)
 	}
 
+	var ttl time.Duration
+
+	if len(cfg.TxTTL) > 0 {
+		var err error
+
+		ttl, err = time.ParseDuration(cfg.TxTTL)
+		if err != nil {
+			log.WithError(err).Fatal("could not parse mempool tx ttl")
+		}
+
+		l = l.WithField("tx_ttl", cfg.TxTTL)
+	}
+
 	m := &Mempool{
 		eventBus:                eventBus,
 		acceptedBlockChan:       acceptedBlockChan,
+		droppedTxsChan:          droppedTxsChan,
 		getMempoolTxsChan:       getMempoolTxsChan,
 		getMempoolTxsBySizeChan: getMempoolTxsBySizeChan,
+		getMempoolViewChan:      getMempoolViewChan,
 		sendTxChan:              sendTxChan,
 		verifier:                verifier,
 		limiter:                 limiter,
 		pendingPropagation:      make(chan TxDesc, 1000),
+		feeLock:                 &sync.RWMutex{},
+		ttl:                     ttl,
+		maxAttempts:             cfg.MaxInclusionAttempts,
+		attempts:                make(map[txHash]uint32),
 		db:                      db,
+		closed:                  make(chan struct{}),
 	}
 
 	// Setting the pool where to cache verified transactions.
@@ -142,6 +213,9 @@ func NewMempool(db database.DB, eventBus *eventbus.EventBus, rpcBus *rpcbus.RPCB
 
 // Run spawns the mempool lifecycle routines.
 func (m *Mempool) Run(ctx context.Context) {
+	// Re-check txs restored from a previous run
+	go m.revalidate()
+
 	// Main Loop
 	go m.Loop(ctx)
 
@@ -160,12 +234,17 @@ func (m *Mempool) Loop(ctx context.Context) {
 			handleRequest(r, m.processGetMempoolTxsRequest, "GetMempoolTxs")
 		case r := <-m.getMempoolTxsBySizeChan:
 			handleRequest(r, m.processGetMempoolTxsBySizeRequest, "GetMempoolTxsBySize")
+		case r := <-m.getMempoolViewChan:
+			handleRequest(r, m.processGetMempoolViewRequest, "GetMempoolView")
 		case b := <-m.acceptedBlockChan:
 			m.onBlock(b)
+		case msg := <-m.droppedTxsChan:
+			m.onDroppedTxs(msg.Payload().(message.Inv))
 		case <-ticker.C:
 			m.onIdle()
 		case <-ctx.Done():
 			m.OnClose()
+			close(m.closed)
 			log.Info("main_loop terminated")
 			return
 		}
@@ -206,14 +285,6 @@ func (m *Mempool) propagateLoop(ctx context.Context) {
 
 // ProcessTx processes a Transaction wire message.
 func (m *Mempool) ProcessTx(srcPeerID string, msg message.Message) ([]bytes.Buffer, error) {
-	maxSizeBytes := config.Get().Mempool.MaxSizeMB * 1000 * 1000
-	if m.verified.Size() > maxSizeBytes {
-		log.WithField("max_size_mb", maxSizeBytes).
-			WithField("alloc_size", m.verified.Size()/1000).
-			Warn("mempool is full, dropping transaction")
-		return nil, errors.New("mempool is full, dropping transaction")
-	}
-
 	// Initializing `h=0` or `h=KadcastInitialHeight` will not work.
 	// This because `h` will be decremented by the kadcast writer as per
 	// it's interpreted as "the kadcast height at which it's been received"
@@ -231,6 +302,17 @@ func (m *Mempool) ProcessTx(srcPeerID string, msg message.Message) ([]bytes.Buff
 		kadHeight: h,
 	}
 
+	if rate, minRate := t.feeRate(), m.getMinFeeRate(); rate < minRate {
+		log.WithField("fee_rate", rate).
+			WithField("min_fee_rate", minRate).
+			WithField("alloc_size", m.verified.Size()/1000).
+			Warn("mempool is full, dropping transaction")
+
+		txid, _ := t.tx.CalculateHash()
+		publishEvent(m.eventBus, Event{Type: Rejected, TxID: txid, Reason: ErrFeeTooLow.Error()})
+		return nil, ErrFeeTooLow
+	}
+
 	start := time.Now()
 	txid, err := m.processTx(t)
 	elapsed := time.Since(start)
@@ -243,17 +325,120 @@ func (m *Mempool) ProcessTx(srcPeerID string, msg message.Message) ([]bytes.Buff
 			WithField("duration", elapsed.Microseconds()).
 			WithField("kad_h", h).
 			Error("failed to accept transaction")
+
+		if errors.Is(err, ErrInvalidTx) {
+			scoring.Report(m.eventBus, srcPeerID, scoring.InvalidTx, err.Error())
+		}
+
+		// The tx is rejected before its hash is updated on preverify failure
+		if txid == nil {
+			txid, _ = t.tx.CalculateHash()
+		}
+
+		publishEvent(m.eventBus, Event{Type: Rejected, TxID: txid, Reason: err.Error()})
 	} else {
 		log.WithField("txid", toHex(txid)).
 			WithField("txtype", t.tx.Type()).
 			WithField("txsize", t.size).
 			WithField("duration", elapsed.Microseconds()).
 			Trace("accepted transaction")
+
+		publishEvent(m.eventBus, Event{Type: Accepted, TxID: txid})
 	}
 
 	return nil, err
 }
 
+// revalidate re-checks the txs restored by the pool from a previous run, as
+// both Rusk and the chain state may have changed while the node was down. The
+// txs no longer valid are deleted.
+func (m *Mempool) revalidate() {
+	restored := make([]TxDesc, 0)
+
+	_ = m.verified.Range(func(k txHash, t TxDesc) error {
+		restored = append(restored, t)
+		return nil
+	})
+
+	if len(restored) == 0 {
+		return
+	}
+
+	var dropped int
+
+	for _, t := range restored {
+		txid, err := t.tx.CalculateHash()
+		if err != nil {
+			continue
+		}
+
+		if err := m.recheck(t.tx, txid); err != nil {
+			// A tx which could not be rechecked (e.g Rusk unavailable) is kept
+			if !errors.Is(err, ErrInvalidTx) && !errors.Is(err, ErrAlreadyExistsInBlockchain) {
+				log.WithError(err).
+					WithField("txid", toHex(txid)).
+					Warn("could not recheck restored transaction")
+				continue
+			}
+
+			log.WithError(err).
+				WithField("txid", toHex(txid)).
+				Debug("drop restored transaction")
+
+			if m.verified.Delete(txid) == nil {
+				dropped++
+
+				publishEvent(m.eventBus, Event{Type: Evicted, TxID: txid, Reason: err.Error()})
+			}
+		}
+	}
+
+	log.WithField("restored", len(restored)-dropped).
+		WithField("dropped", dropped).
+		Info("restored transactions revalidated")
+}
+
+// recheck ensures a tx is still accepted by Rusk and not yet in the
+// blockchain.
+func (m *Mempool) recheck(tx transactions.ContractCall, txid []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(config.Get().RPC.Rusk.ContractTimeout)*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := m.verifier.Preverify(ctx, tx); err != nil {
+		return preverifyError(err)
+	}
+
+	err := m.db.View(func(t database.Transaction) error {
+		_, _, _, err := t.FetchBlockTxByHash(txid)
+		return err
+	})
+
+	switch err {
+	case database.ErrTxNotFound:
+		return nil
+	case nil:
+		return ErrAlreadyExistsInBlockchain
+	default:
+		return err
+	}
+}
+
+// preverifyError marks a Preverify error as ErrInvalidTx, unless it results
+// from a failure to reach Rusk. Only invalid transactions are held against the
+// peer which relayed them.
+func preverifyError(err error) error {
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Canceled, codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted,
+			codes.Aborted, codes.Unimplemented, codes.Internal, codes.Unavailable:
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidTx, err)
+}
+
 // processTx ensures all transaction rules are satisfied before adding the tx
 // into the verified pool.
 func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
@@ -267,7 +452,7 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 	defer cancel()
 
 	if hash, _, err = m.verifier.Preverify(ctx, t.tx); err != nil {
-		return nil, err
+		return nil, preverifyError(err)
 	}
 
 	t.tx, err = transactions.UpdateHash(t.tx, hash)
@@ -285,8 +470,9 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 		return txid, ErrAlreadyExists
 	}
 
-	// ensure nullifier does not exist in the mempool state
-	err = m.containNullifier(t.tx)
+	// ensure nullifier does not exist in the mempool state, unless the
+	// transaction replaces the ones spending it
+	replaced, err := m.checkNullifiers(t.tx)
 	if err != nil {
 		return txid, err
 	}
@@ -301,11 +487,20 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 	case database.ErrTxNotFound:
 		t.verified = time.Now()
 
+		// the room held by the replaced txs is released along with the
+		// replacement
+		if err = m.makeRoom(t, m.sizeOf(replaced)); err != nil {
+			return txid, err
+		}
+
 		// store transaction in mempool
 		if err = m.verified.Put(t); err != nil {
 			return txid, fmt.Errorf("store err - %v", err)
 		}
 
+		// the replaced txs are deleted only once the replacement is stored
+		m.replace(txid, replaced)
+
 		// queue transaction for (re)propagation
 		go func() {
 			m.pendingPropagation <- t
@@ -319,12 +514,67 @@ func (m *Mempool) processTx(t TxDesc) ([]byte, error) {
 	}
 }
 
+// makeRoom evicts the txs with the lowest fee rate if there is not enough room
+// left in the mempool for the given one, once the released bytes are freed.
+// Only txs paying a lower fee rate than the given one can be evicted. The
+// minimum fee rate is raised to the highest fee rate evicted.
+func (m *Mempool) makeRoom(t TxDesc, released uint32) error {
+	maxSizeBytes := config.Get().Mempool.MaxSizeMB * 1000 * 1000
+
+	size := m.verified.Size() + uint32(t.size)
+	if size <= maxSizeBytes+released {
+		return nil
+	}
+
+	size -= released
+
+	rate := t.feeRate()
+
+	evicted, err := m.verified.Evict(size-maxSizeBytes, rate)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMempoolFull, err)
+	}
+
+	m.feeLock.Lock()
+	defer m.feeLock.Unlock()
+
+	for _, e := range evicted {
+		if r := e.feeRate(); r > m.minFeeRate {
+			m.minFeeRate = r
+		}
+
+		txid, _ := e.tx.CalculateHash()
+
+		log.WithField("txid", toHex(txid)).
+			WithField("fee_rate", e.feeRate()).
+			WithField("replaced_by_fee_rate", rate).
+			Info("evicted transaction")
+
+		publishEvent(m.eventBus, Event{Type: Evicted, TxID: txid, Reason: "low fee rate"})
+	}
+
+	return nil
+}
+
+func (m *Mempool) getMinFeeRate() float64 {
+	m.feeLock.RLock()
+	defer m.feeLock.RUnlock()
+	return m.minFeeRate
+}
+
 // onBlock performs post-block-acceptance procedure to update mempool state
 // accordingly.
 func (m *Mempool) onBlock(b block.Block) {
 	// Discard transactions that are accepted with this block.
 	// This is the case when the accepted block has been proposed by another provisioner.
-	m.discardAcceptedTxs(b.Txs)
+	m.discardAcceptedTxs(b.Txs, b.Header.Height)
+
+	m.expireTxs()
+
+	// Accepted txs freed some room, lower the minimum fee rate.
+	m.feeLock.Lock()
+	m.minFeeRate /= 2
+	m.feeLock.Unlock()
 
 	log.WithField("height", b.Header.Height).
 		WithField("txs_count", len(b.Txs)).
@@ -340,7 +590,7 @@ func (m *Mempool) onBlock(b block.Block) {
 // update.
 //
 // The passed block is supposed to be the last one accepted.
-func (m *Mempool) discardAcceptedTxs(txs []transactions.ContractCall) {
+func (m *Mempool) discardAcceptedTxs(txs []transactions.ContractCall, height uint64) {
 	if m.verified.Len() == 0 {
 		// Empty pool then no need for cleanup
 		return
@@ -352,38 +602,226 @@ func (m *Mempool) discardAcceptedTxs(txs []transactions.ContractCall) {
 			log.WithError(err).Panic("could not calculate tx hash")
 		}
 
-		_ = m.verified.Delete(hash)
+		if m.verified.Delete(hash) == nil {
+			publishEvent(m.eventBus, Event{Type: Included, TxID: hash, Height: height})
+		}
+
+		var k txHash
+
+		copy(k[:], hash)
+		delete(m.attempts, k)
 	}
 }
 
-func (m Mempool) containNullifier(tx transactions.ContractCall) error {
+// checkNullifiers ensures the nullifiers of the tx are not spent by pooled txs.
+// Otherwise, the tx may replace them if it spends a superset of their
+// nullifiers and raises their gas price by the replace-by-fee margin. The ids
+// of the txs to be replaced are returned.
+func (m Mempool) checkNullifiers(tx transactions.ContractCall) ([][]byte, error) {
 	decoded, err := tx.Decode()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	found, repeatedNullifier := m.verified.ContainAnyNullifiers(decoded.Nullifiers)
+	if !found {
+		return nil, nil
 	}
 
-	if found, repeatedNullifier := m.verified.ContainAnyNullifiers(decoded.Nullifiers); found {
-		h := hex.EncodeToString(repeatedNullifier)
+	l := log.WithField("repeated_nullifier", hex.EncodeToString(repeatedNullifier))
 
-		log.WithField("repeated_nullifier", h).Warn(ErrNullifierExists.Error())
-		return ErrNullifierExists
+	spent := make(map[string]bool, len(decoded.Nullifiers))
+	for _, n := range decoded.Nullifiers {
+		spent[string(n)] = true
 	}
 
-	return nil
+	margin := config.Get().Mempool.ReplaceByFeeMargin
+	if margin == 0 {
+		margin = defaultReplaceByFeeMargin
+	}
+
+	replaced := make([][]byte, 0)
+	visited := make(map[string]bool)
+
+	for _, n := range decoded.Nullifiers {
+		txids, err := m.verified.GetTxsByNullifier(n)
+		if err != nil {
+			// nullifier not spent by pooled txs
+			continue
+		}
+
+		for _, txid := range txids {
+			if visited[string(txid)] {
+				continue
+			}
+
+			visited[string(txid)] = true
+
+			pooled := m.verified.Get(txid)
+			if pooled == nil {
+				continue
+			}
+
+			d, err := pooled.Decode()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, pn := range d.Nullifiers {
+				if !spent[string(pn)] {
+					l.Warn(ErrNullifierExists.Error())
+					return nil, ErrNullifierExists
+				}
+			}
+
+			// GasPrice must be raised by at least margin percent
+			minPrice := d.Fee.GasPrice + d.Fee.GasPrice*uint64(margin)/100
+			if decoded.Fee.GasPrice <= d.Fee.GasPrice || decoded.Fee.GasPrice < minPrice {
+				l.WithField("gas_price", decoded.Fee.GasPrice).
+					WithField("min_gas_price", minPrice).
+					Warn(ErrReplacementUnderpriced.Error())
+				return nil, ErrReplacementUnderpriced
+			}
+
+			replaced = append(replaced, txid)
+		}
+	}
+
+	return replaced, nil
+}
+
+// sizeOf returns the overall size of the pooled txs with the given ids.
+func (m *Mempool) sizeOf(txids [][]byte) uint32 {
+	if len(txids) == 0 {
+		return 0
+	}
+
+	ids := make(map[txHash]bool, len(txids))
+
+	for _, txid := range txids {
+		var k txHash
+
+		copy(k[:], txid)
+		ids[k] = true
+	}
+
+	var size uint32
+
+	_ = m.verified.Range(func(k txHash, t TxDesc) error {
+		if ids[k] {
+			size += uint32(t.size)
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+// replace deletes the txs replaced by the tx with the given id, and announces
+// both the replacement and the evictions.
+func (m *Mempool) replace(txid []byte, replaced [][]byte) {
+	if len(replaced) == 0 {
+		return
+	}
+
+	for _, r := range replaced {
+		// A replaced tx may have been evicted already
+		if err := m.verified.Delete(r); err != nil {
+			continue
+		}
+
+		log.WithField("txid", toHex(r)).
+			WithField("replaced_by", toHex(txid)).
+			Info("replaced transaction")
+
+		publishEvent(m.eventBus, Event{Type: Evicted, TxID: r, Reason: "replaced by fee"})
+	}
+
+	publishEvent(m.eventBus, Event{Type: Replacement, TxID: txid, Replaced: replaced})
 }
 
-// TODO: Get rid of stuck/expired transactions.
 func (m *Mempool) onIdle() {
+	m.expireTxs()
+
 	log.
 		WithField("alloc_size", int64(m.verified.Size())/1000).
 		WithField("txs_count", m.verified.Len()).Info("process_on_idle")
 }
 
+// onDroppedTxs counts an inclusion attempt for each pooled tx dropped by Rusk
+// from a candidate block.
+func (m *Mempool) onDroppedTxs(inv message.Inv) {
+	for _, item := range inv.InvList {
+		if !m.verified.Contain(item.Hash) {
+			continue
+		}
+
+		var k txHash
+
+		copy(k[:], item.Hash)
+		m.attempts[k]++
+	}
+}
+
+// expireTxs drops the txs older than the ttl, and the ones dropped from a
+// candidate block maxAttempts times. The latter are likely rejected by Rusk
+// at block generation.
+func (m *Mempool) expireTxs() {
+	if m.ttl == 0 && m.maxAttempts == 0 {
+		return
+	}
+
+	expired := make(map[txHash]string)
+	now := time.Now()
+
+	_ = m.verified.Range(func(k txHash, t TxDesc) error {
+		switch {
+		case m.ttl > 0 && now.Sub(t.received) > m.ttl:
+			expired[k] = "ttl exceeded"
+		case m.maxAttempts > 0 && m.attempts[k] >= m.maxAttempts:
+			expired[k] = "max inclusion attempts reached"
+		}
+
+		return nil
+	})
+
+	for k, reason := range expired {
+		txid := make([]byte, len(k))
+		copy(txid, k[:])
+
+		// Range holds the pool lock, txs are deleted afterwards
+		if err := m.verified.Delete(txid); err != nil {
+			continue
+		}
+
+		log.WithField("txid", toHex(txid)).
+			WithField("reason", reason).
+			Info("expired transaction")
+
+		publishEvent(m.eventBus, Event{Type: Expired, TxID: txid, Reason: reason})
+	}
+
+	// Forget the attempts of the txs no longer in the mempool
+	for k := range m.attempts {
+		if !m.verified.Contain(k[:]) {
+			delete(m.attempts, k)
+		}
+	}
+}
+
 func (m *Mempool) newPool() Pool {
 	cfg := config.Get().Mempool
 
 	var p Pool
 
+	// A relative dump file is kept along with the chain database, not in the
+	// working directory
+	path := cfg.HashMapDumpFile
+	if len(path) > 0 && !filepath.IsAbs(path) {
+		path = filepath.Join(config.Get().Database.Dir, path)
+	}
+
 	switch cfg.PoolType {
 	case backendHashmap:
 		p = &HashMap{
@@ -392,6 +830,7 @@ func (m *Mempool) newPool() Pool {
 		}
 	case backendDiskpool:
 		p = new(buntdbPool)
+		path = cfg.DiskPoolDir
 	default:
 		p = &HashMap{
 			lock:     &sync.RWMutex{},
@@ -399,7 +838,7 @@ func (m *Mempool) newPool() Pool {
 		}
 	}
 
-	if err := p.Create(cfg.DiskPoolDir); err != nil {
+	if err := p.Create(path); err != nil {
 		log.WithField("pool", cfg.PoolType).WithError(err).Fatal("failed to create pool")
 	}
 
@@ -498,6 +937,16 @@ func (m Mempool) processGetMempoolTxsBySizeRequest(r rpcbus.Request) (interface{
 	return txs, err
 }
 
+// processGetMempoolViewRequest returns a View of the mempool state.
+func (m Mempool) processGetMempoolViewRequest(r rpcbus.Request) (interface{}, error) {
+	return View{
+		TxsCount:   m.verified.Len(),
+		Size:       m.verified.Size(),
+		MaxSize:    config.Get().Mempool.MaxSizeMB * 1000 * 1000,
+		MinFeeRate: m.getMinFeeRate(),
+	}, nil
+}
+
 // kadcastTx (re)propagates transaction in kadcast network.
 func (m *Mempool) kadcastTx(t TxDesc) error {
 	/// repropagate
@@ -544,6 +993,12 @@ func (m *Mempool) RequestUpdates() {
 	m.eventBus.Publish(topics.KadcastSendToMany, msg)
 }
 
+// Wait blocks until the main loop is terminated on canceling mempool context
+// and the pool is closed. Txs of a hashmap pool are dumped by then.
+func (m *Mempool) Wait() {
+	<-m.closed
+}
+
 // OnClose performs mempool cleanup procedure. It's called on canceling mempool
 // context.
 func (m *Mempool) OnClose() {
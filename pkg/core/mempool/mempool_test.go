@@ -9,6 +9,8 @@ package mempool
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"errors"
 	"math"
 	"os"
 	"sync"
@@ -19,13 +21,17 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	assert "github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestMain(m *testing.M) {
@@ -217,6 +223,321 @@ func TestRemoveAccepted(t *testing.T) {
 	}
 }
 
+func TestReplaceByFee(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m, bus, _, _ := startMempoolTest(ctx)
+
+	eventChan := make(chan message.Message, 10)
+	bus.Subscribe(topics.MempoolEvent, eventbus.NewChanListener(eventChan))
+
+	// All txs spend the same nullifier
+	base := transactions.RandTx()
+
+	tx := withGasPrice(t, base, 100)
+	_, err := m.ProcessTx("", message.New(topics.Tx, tx))
+	assert.NoError(err)
+
+	// GasPrice is not raised by the margin
+	_, err = m.ProcessTx("", message.New(topics.Tx, withGasPrice(t, base, 105)))
+	assert.True(errors.Is(err, ErrReplacementUnderpriced))
+
+	replacement := withGasPrice(t, base, 110)
+	_, err = m.ProcessTx("", message.New(topics.Tx, replacement))
+	assert.NoError(err)
+
+	txid, _ := tx.CalculateHash()
+	replacementID, _ := replacement.CalculateHash()
+
+	assert.False(m.verified.Contain(txid))
+	assert.True(m.verified.Contain(replacementID))
+	assert.Equal(1, m.verified.Len())
+
+	evicted := nextEvent(t, eventChan, Evicted)
+	assert.Equal(txid, evicted.TxID)
+
+	replaced := nextEvent(t, eventChan, Replacement)
+	assert.Equal(replacementID, replaced.TxID)
+	assert.Equal([][]byte{txid}, replaced.Replaced)
+}
+
+// TestReplaceByFeeFullMempool ensures the room held by the replaced txs is
+// taken into account when making room for the replacement.
+func TestReplaceByFeeFullMempool(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m, _, _, _ := startMempoolTest(ctx)
+
+	// An unrelated tx paying a low fee rate
+	other := withGasPrice(t, transactions.RandTx(), 40)
+	_, err := m.processTx(TxDesc{tx: other, received: time.Now(), size: 300000})
+	assert.NoError(err)
+
+	base := transactions.RandTx()
+
+	tx := withGasPrice(t, base, 100)
+	_, err = m.processTx(TxDesc{tx: tx, received: time.Now(), size: 600000})
+	assert.NoError(err)
+
+	// The replacement does not fit unless the replaced tx is accounted for
+	replacement := withGasPrice(t, base, 110)
+	_, err = m.processTx(TxDesc{tx: replacement, received: time.Now(), size: 600000})
+	assert.NoError(err)
+
+	otherID, _ := other.CalculateHash()
+	txid, _ := tx.CalculateHash()
+	replacementID, _ := replacement.CalculateHash()
+
+	assert.True(m.verified.Contain(otherID))
+	assert.False(m.verified.Contain(txid))
+	assert.True(m.verified.Contain(replacementID))
+	assert.Equal(uint32(900000), m.verified.Size())
+	assert.Zero(m.getMinFeeRate())
+}
+
+// TestMinFeeRate ensures the cheapest txs are evicted to make room for better
+// paying ones, and that the minimum fee rate is raised on eviction and halved
+// on each accepted block.
+func TestMinFeeRate(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m, _, _, _ := startMempoolTest(ctx)
+
+	cheap := TxDesc{tx: withGasPrice(t, transactions.RandTx(), 10), received: time.Now(), size: 600000}
+	_, err := m.processTx(cheap)
+	assert.NoError(err)
+	assert.Zero(m.getMinFeeRate())
+
+	// The cheap tx is evicted to make room for a better paying one
+	better := TxDesc{tx: withGasPrice(t, transactions.RandTx(), 100), received: time.Now(), size: 600000}
+	_, err = m.processTx(better)
+	assert.NoError(err)
+
+	cheapID, _ := cheap.tx.CalculateHash()
+	betterID, _ := better.tx.CalculateHash()
+
+	assert.False(m.verified.Contain(cheapID))
+	assert.True(m.verified.Contain(betterID))
+	assert.Equal(cheap.feeRate(), m.getMinFeeRate())
+
+	// No room can be made by evicting txs paying a higher fee rate
+	_, err = m.processTx(TxDesc{tx: withGasPrice(t, transactions.RandTx(), 50), received: time.Now(), size: 600000})
+	assert.True(errors.Is(err, ErrMempoolFull))
+	assert.Equal(1, m.verified.Len())
+
+	// Txs paying less than the minimum fee rate are rejected upfront
+	_, err = m.ProcessTx("", message.New(topics.Tx, withGasPrice(t, transactions.RandTx(), 0)))
+	assert.True(errors.Is(err, ErrFeeTooLow))
+
+	view, err := m.processGetMempoolViewRequest(rpcbus.Request{})
+	assert.NoError(err)
+	assert.Equal(View{
+		TxsCount:   1,
+		Size:       600000,
+		MaxSize:    1000000,
+		MinFeeRate: cheap.feeRate(),
+	}, view)
+
+	// The minimum fee rate is halved on each accepted block
+	m.onBlock(*helper.RandomBlock(1, 0))
+	assert.Equal(cheap.feeRate()/2, m.getMinFeeRate())
+}
+
+func TestMempoolEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m, bus, _, _ := startMempoolTest(ctx)
+
+	eventChan := make(chan message.Message, 10)
+	bus.Subscribe(topics.MempoolEvent, eventbus.NewChanListener(eventChan))
+
+	tx := transactions.MockTxWithParams(transactions.Transfer, 0)
+	txid, _ := tx.CalculateHash()
+
+	_, err := m.ProcessTx("", message.New(topics.Tx, tx.Copy()))
+	assert.NoError(err)
+
+	e := nextEvent(t, eventChan, Accepted)
+	assert.Equal(txid, e.TxID)
+
+	_, err = m.ProcessTx("", message.New(topics.Tx, tx.Copy()))
+	assert.Error(err)
+
+	e = nextEvent(t, eventChan, Rejected)
+	assert.Equal(txid, e.TxID)
+	assert.Equal(ErrAlreadyExists.Error(), e.Reason)
+
+	// The tx is included in an accepted block
+	b := helper.RandomBlock(200, 0)
+	b.Txs = []transactions.ContractCall{tx}
+
+	errList := bus.Publish(topics.AcceptedBlock, message.New(topics.AcceptedBlock, *b))
+	assert.Empty(errList)
+
+	e = nextEvent(t, eventChan, Included)
+	assert.Equal(txid, e.TxID)
+	assert.Equal(b.Header.Height, e.Height)
+}
+
+// nextEvent skips the mempool events until one of the given type.
+func nextEvent(t *testing.T, eventChan chan message.Message, eventType EventType) Event {
+	for {
+		select {
+		case m := <-eventChan:
+			if e := m.Payload().(Event); e.Type == eventType {
+				return e
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no %s event", eventType)
+		}
+	}
+}
+
+// withGasPrice returns a copy of the tx paying the given gas price.
+func withGasPrice(t *testing.T, tx *transactions.Transaction, price uint64) *transactions.Transaction {
+	c := tx.Copy().(*transactions.Transaction)
+	data := c.Payload.Data
+
+	// Skip nullifiers, notes and anchor to reach the fee
+	buf := bytes.NewBuffer(data)
+
+	var count uint64
+	assert.NoError(t, encoding.ReadUint64LE(buf, &count))
+	buf.Next(int(count) * 32)
+
+	assert.NoError(t, encoding.ReadUint64LE(buf, &count))
+
+	for i := uint64(0); i < count; i++ {
+		assert.NoError(t, transactions.UnmarshalNote(buf, transactions.NewNote()))
+	}
+
+	buf.Next(32)
+
+	// GasPrice follows GasLimit
+	offset := len(data) - buf.Len() + 8
+	binary.LittleEndian.PutUint64(data[offset:offset+8], price)
+
+	decoded, err := c.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, price, decoded.Fee.GasPrice)
+
+	hash, err := decoded.Hash(c.TxType)
+	assert.NoError(t, err)
+
+	copy(c.Hash[:], hash)
+	return c
+}
+
+func TestExpireTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := eventbus.New()
+	_, db := lite.CreateDBConnection()
+	v := &transactions.MockProxy{}
+
+	m := NewMempool(db, bus, rpcbus.New(), v.Prober())
+	m.ttl = time.Minute
+	m.maxAttempts = 2
+
+	eventChan := make(chan message.Message, 2)
+	bus.Subscribe(topics.MempoolEvent, eventbus.NewChanListener(eventChan))
+
+	txs := transactions.RandContractCalls(3, 0, false)
+	hashes := make([]txHash, len(txs))
+
+	for i, tx := range txs {
+		td := TxDesc{tx: tx, received: time.Now()}
+
+		// The second tx outlived the ttl
+		if i == 1 {
+			td.received = td.received.Add(-2 * time.Minute)
+		}
+
+		assert.NoError(m.verified.Put(td))
+
+		txid, _ := tx.CalculateHash()
+		copy(hashes[i][:], txid)
+	}
+
+	// The third tx was dropped twice from a candidate block, along with a tx
+	// not in the mempool
+	dropped := message.Inv{}
+	dropped.AddItem(message.InvTypeMempoolTx, hashes[2][:])
+	dropped.AddItem(message.InvTypeMempoolTx, make([]byte, 32))
+
+	m.onDroppedTxs(dropped)
+	m.onDroppedTxs(dropped)
+	assert.Len(m.attempts, 1)
+
+	m.expireTxs()
+
+	assert.True(m.verified.Contain(hashes[0][:]))
+	assert.False(m.verified.Contain(hashes[1][:]))
+	assert.False(m.verified.Contain(hashes[2][:]))
+	assert.Empty(m.attempts)
+
+	for i := 0; i < 2; i++ {
+		e := (<-eventChan).Payload().(Event)
+		assert.Equal(Expired, e.Type)
+		assert.NotEmpty(e.Reason)
+	}
+}
+
+func TestRevalidate(t *testing.T) {
+	assert := assert.New(t)
+
+	_, db := lite.CreateDBConnection()
+	v := &transactions.MockProxy{}
+
+	m := NewMempool(db, eventbus.New(), rpcbus.New(), v.Prober())
+
+	txs := transactions.RandContractCalls(2, 0, false)
+	for _, tx := range txs {
+		assert.NoError(m.verified.Put(TxDesc{tx: tx, received: time.Now()}))
+	}
+
+	// The second tx was included in a block while the node was down
+	b := helper.RandomBlock(200, 0)
+	b.Txs = []transactions.ContractCall{txs[1]}
+
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		return t.StoreBlock(b, true)
+	}))
+
+	m.revalidate()
+
+	hash0, _ := txs[0].CalculateHash()
+	hash1, _ := txs[1].CalculateHash()
+
+	assert.True(m.verified.Contain(hash0))
+	assert.False(m.verified.Contain(hash1))
+
+	// Txs which cannot be rechecked are kept
+	m.verifier = unavailableProber{}
+	m.revalidate()
+
+	assert.True(m.verified.Contain(hash0))
+}
+
+// unavailableProber fails to reach Rusk.
+type unavailableProber struct{}
+
+func (unavailableProber) Preverify(context.Context, transactions.ContractCall) ([]byte, transactions.Fee, error) {
+	return nil, transactions.Fee{}, status.Error(codes.Unavailable, "rusk unavailable")
+}
+
 func BenchmarkProcessTx_0(b *testing.B) {
 	// Recent result
 	// BenchmarkProcessTx_0-8             50475             33671 ns/op
@@ -278,3 +599,14 @@ func benchmarkProcessTx(b *testing.B, batchCount int, verifyTransactionLatency t
 		b.Fatalf("not all txs accepted %d - %d", len(txs), m.verified.Len())
 	}
 }
+
+// TestPreverifyError ensures only transactions rejected by the verifier are
+// held against the peer, not failures to reach Rusk.
+func TestPreverifyError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(errors.Is(preverifyError(errors.New("tx gas limit exceeds the block gas limit")), ErrInvalidTx))
+	assert.True(errors.Is(preverifyError(status.Error(codes.InvalidArgument, "invalid proof")), ErrInvalidTx))
+	assert.False(errors.Is(preverifyError(status.Error(codes.Unavailable, "connection refused")), ErrInvalidTx))
+	assert.False(errors.Is(preverifyError(status.Error(codes.DeadlineExceeded, "deadline exceeded")), ErrInvalidTx))
+}
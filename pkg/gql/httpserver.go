@@ -36,6 +36,14 @@ const (
 	endpointWS  = "/ws"
 	endpointWSS = "/wss"
 	endpointGQL = "/graphql"
+
+	// Sync progress notifications endpoints.
+	endpointSyncWS  = "/ws/sync"
+	endpointSyncWSS = "/wss/sync"
+
+	// Mempool notifications endpoints.
+	endpointMempoolWS  = "/ws/mempool"
+	endpointMempoolWSS = "/wss/mempool"
 )
 
 // Server defines the HTTP server of the GraphQL service node.
@@ -48,8 +56,10 @@ type Server struct {
 	// Graphql utility.
 	schema *graphql.Schema
 
-	// Websocket connections pool.
-	pool *notifications.BrokerPool
+	// Websocket connections pools.
+	pool        *notifications.BrokerPool
+	syncPool    *notifications.BrokerPool
+	mempoolPool *notifications.BrokerPool
 
 	// Node components.
 	eventBus *eventbus.EventBus
@@ -188,8 +198,25 @@ func (s *Server) EnableNotifications(serverMux *http.ServeMux) error {
 		Info("Start graphql notification service")
 
 	s.pool = notifications.NewPool(s.eventBus, nc.BrokersNum, clientsPerBroker)
+	s.syncPool = notifications.NewSyncProgressPool(s.eventBus, nc.BrokersNum, clientsPerBroker)
+	s.mempoolPool = notifications.NewMempoolPool(s.eventBus, nc.BrokersNum, clientsPerBroker)
+
+	endpoint, syncEndpoint, mempoolEndpoint := endpointWS, endpointSyncWS, endpointMempoolWS
+	if cfg.Get().Gql.EnableTLS {
+		endpoint, syncEndpoint, mempoolEndpoint = endpointWSS, endpointSyncWSS, endpointMempoolWSS
+	}
+
+	serverMux.Handle(endpoint, tollbooth.LimitFuncHandler(s.lmt, s.wsHandler(upgrader, s.pool)))
+	serverMux.Handle(syncEndpoint, tollbooth.LimitFuncHandler(s.lmt, s.wsHandler(upgrader, s.syncPool)))
+	serverMux.Handle(mempoolEndpoint, tollbooth.LimitFuncHandler(s.lmt, s.wsHandler(upgrader, s.mempoolPool)))
+
+	return nil
+}
 
-	wsHandler := func(w http.ResponseWriter, r *http.Request) {
+// wsHandler upgrades the requests to websocket connections, and pushes them to
+// a pool of notification brokers.
+func (s *Server) wsHandler(upgrader *websocket.Upgrader, pool *notifications.BrokerPool) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
 		if atomic.LoadUint32(&s.started) == 0 {
 			return
 		}
@@ -200,27 +227,18 @@ func (s *Server) EnableNotifications(serverMux *http.ServeMux) error {
 			return
 		}
 
-		s.pool.PushConn(conn)
-	}
-
-	middleware := tollbooth.LimitFuncHandler(s.lmt, wsHandler)
-
-	endpoint := endpointWS
-	if cfg.Get().Gql.EnableTLS {
-		endpoint = endpointWSS
+		pool.PushConn(conn)
 	}
-
-	serverMux.Handle(endpoint, middleware)
-
-	return nil
 }
 
 // Close closes the server.
 func (s *Server) Close() error {
 	atomic.StoreUint32(&s.started, 0)
 
-	// Close pool of notification brokers
+	// Close pools of notification brokers
 	s.pool.Close()
+	s.syncPool.Close()
+	s.mempoolPool.Close()
 
 	// Close graphql http server
 	dialCtx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -10,8 +10,8 @@ import (
 	"container/list"
 	"time"
 
-	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus"
-	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 	"github.com/sirupsen/logrus"
@@ -28,8 +28,23 @@ const (
 var log = logrus.WithField("process", "consensus").
 	WithField("actor", "broker")
 
+// marshalFunc encodes an EventBus event into a notification message.
+type marshalFunc func(message.Message) (string, error)
+
+// listenerFunc creates the EventBus listener forwarding events to a broker.
+type listenerFunc func(chan<- message.Message) eventbus.Listener
+
+// newCallbackListener forwards events from a goroutine per event. Events are
+// never dropped, but their order is not preserved.
+func newCallbackListener(eventChan chan<- message.Message) eventbus.Listener {
+	return eventbus.NewSafeCallbackListener(func(m message.Message) {
+		eventChan <- m
+	})
+}
+
 // Broker is a pub/sub broker that keeps updated all subscribers (websocket
-// connections) with latest block accepted published by node layer.
+// connections) with the events of a topic published by node layer (e.g latest
+// block accepted).
 //
 // IMPL Notes:
 // Broker is implemented in a non-blocking manner. That means it should not be
@@ -48,24 +63,45 @@ type Broker struct {
 	ConnectionChan chan wsConn
 
 	// Events.
-	eventBus          eventbus.Broker
-	acceptedBlockChan chan block.Block
-	acceptedBlockID   uint32
+	eventBus  eventbus.Broker
+	topic     topics.Topic
+	marshal   marshalFunc
+	eventChan chan message.Message
+	eventID   uint32
 }
 
-// NewBroker creates a new Broker instance.
+// NewBroker creates a new Broker instance notifying the accepted blocks.
 func NewBroker(id uint, eventBus eventbus.Broker, maxClientsCount uint, connChan chan wsConn) *Broker {
+	return newBroker(id, eventBus, topics.AcceptedBlock, marshalBlockEvent, newCallbackListener, maxClientsCount, connChan)
+}
+
+// NewSyncProgressBroker creates a new Broker instance notifying the sync
+// progress.
+func NewSyncProgressBroker(id uint, eventBus eventbus.Broker, maxClientsCount uint, connChan chan wsConn) *Broker {
+	return newBroker(id, eventBus, topics.SyncProgress, marshalSyncProgressEvent, newCallbackListener, maxClientsCount, connChan)
+}
+
+// NewMempoolBroker creates a new Broker instance notifying the mempool events.
+// Events are notified in order. They are dropped if the broker falls behind.
+func NewMempoolBroker(id uint, eventBus eventbus.Broker, maxClientsCount uint, connChan chan wsConn) *Broker {
+	return newBroker(id, eventBus, topics.MempoolEvent, marshalMempoolEvent, eventbus.NewChanListener, maxClientsCount, connChan)
+}
+
+func newBroker(id uint, eventBus eventbus.Broker, topic topics.Topic, marshal marshalFunc, listener listenerFunc, maxClientsCount uint, connChan chan wsConn) *Broker {
 	b := new(Broker)
 	b.eventBus = eventBus
 	b.ConnectionChan = connChan
-	b.acceptedBlockChan, b.acceptedBlockID = consensus.InitAcceptedBlockUpdate(eventBus)
+	b.topic = topic
+	b.marshal = marshal
+	b.eventChan = make(chan message.Message, cfg.MaxInvBlocks)
+	b.eventID = eventBus.Subscribe(topic, listener(b.eventChan))
 	b.clients = list.New()
 	b.maxClientsCount = maxClientsCount
 	b.id = id
 	return b
 }
 
-// Run represents the main loop of the Broker, where block data get piped to
+// Run represents the main loop of the Broker, where event data get piped to
 // incoming connections. Connections are put in Idle state after 30 seconds of
 // inactivity.
 func (b *Broker) Run() {
@@ -76,7 +112,7 @@ func (b *Broker) Run() {
 		log.WithField("id", b.id).Info("closing")
 
 		// Unsubscribe from all eventBus events.
-		b.eventBus.Unsubscribe(topics.AcceptedBlock, b.acceptedBlockID)
+		b.eventBus.Unsubscribe(b.topic, b.eventID)
 
 		// Terminate all clients goroutines.
 		for e := b.clients.Front(); e != nil; e = e.Next() {
@@ -99,27 +135,27 @@ func (b *Broker) Run() {
 			}
 
 			b.handleConn(conn)
-		// new accepted block from node
-		case blk := <-b.acceptedBlockChan:
-			b.handleBlock(blk)
+		// new event from node
+		case m := <-b.eventChan:
+			b.handleEvent(m)
 		case <-time.After(30 * time.Second):
 			b.handleIdle()
 		}
 	}
 }
 
-// handleBlock handles the topics.AcceptedBlock event emitted from node layer.
-// It packs a json from the block and broadcast it to all active clients.
-func (b *Broker) handleBlock(blk block.Block) {
+// handleEvent handles an event of the broker topic emitted from node layer.
+// It packs a json from the event and broadcast it to all active clients.
+func (b *Broker) handleEvent(m message.Message) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Errorf("handleBlock recovered from err: %v", r)
+			log.Errorf("handleEvent recovered from err: %v", r)
 		}
 	}()
 
 	b.reap()
 
-	msg, err := MarshalBlockMsg(blk)
+	msg, err := b.marshal(m)
 	if err != nil {
 		log.Errorf("encoding err: %v", err)
 	}
@@ -11,6 +11,9 @@ import (
 	"encoding/json"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/syncprogress"
 )
 
 // BlockMsg represents the data need by Explorer UI on each new block accepted.
@@ -58,3 +61,55 @@ func MarshalBlockMsg(blk block.Block) (string, error) {
 
 	return string(msg), nil
 }
+
+func marshalBlockEvent(m message.Message) (string, error) {
+	return MarshalBlockMsg(m.Payload().(block.Block))
+}
+
+// MarshalSyncProgressMsg builds the JSON of a sync progress update.
+func MarshalSyncProgressMsg(p syncprogress.Progress) (string, error) {
+	msg, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	return string(msg), nil
+}
+
+func marshalSyncProgressEvent(m message.Message) (string, error) {
+	return MarshalSyncProgressMsg(m.Payload().(syncprogress.Progress))
+}
+
+// MempoolMsg represents a change of the mempool state of a transaction.
+type MempoolMsg struct {
+	Type     string   `json:"type"`
+	TxID     string   `json:"txid"`
+	Reason   string   `json:"reason,omitempty"`
+	Replaced []string `json:"replaced,omitempty"`
+	Height   uint64   `json:"height,omitempty"`
+}
+
+// MarshalMempoolMsg builds the JSON of a mempool event.
+func MarshalMempoolMsg(e mempool.Event) (string, error) {
+	p := MempoolMsg{
+		Type:   e.Type.String(),
+		TxID:   hex.EncodeToString(e.TxID),
+		Reason: e.Reason,
+		Height: e.Height,
+	}
+
+	for _, txid := range e.Replaced {
+		p.Replaced = append(p.Replaced, hex.EncodeToString(txid))
+	}
+
+	msg, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	return string(msg), nil
+}
+
+func marshalMempoolEvent(m message.Message) (string, error) {
+	return MarshalMempoolMsg(m.Payload().(mempool.Event))
+}
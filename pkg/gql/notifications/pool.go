@@ -41,13 +41,27 @@ type BrokerPool struct {
 // goroutines. Thus it returns a new BrokerPool instance populated with said
 // brokers.
 func NewPool(eventBus *eventbus.EventBus, brokersNum, clientsPerBroker uint) *BrokerPool {
+	return newPool(eventBus, NewBroker, brokersNum, clientsPerBroker)
+}
+
+// NewSyncProgressPool intantiates a BrokerPool notifying the sync progress.
+func NewSyncProgressPool(eventBus *eventbus.EventBus, brokersNum, clientsPerBroker uint) *BrokerPool {
+	return newPool(eventBus, NewSyncProgressBroker, brokersNum, clientsPerBroker)
+}
+
+// NewMempoolPool intantiates a BrokerPool notifying the mempool events.
+func NewMempoolPool(eventBus *eventbus.EventBus, brokersNum, clientsPerBroker uint) *BrokerPool {
+	return newPool(eventBus, NewMempoolBroker, brokersNum, clientsPerBroker)
+}
+
+func newPool(eventBus *eventbus.EventBus, newBroker func(uint, eventbus.Broker, uint, chan wsConn) *Broker, brokersNum, clientsPerBroker uint) *BrokerPool {
 	bp := new(BrokerPool)
 	bp.workers = make([]*Broker, 0)
 	bp.ConnectionsChan = make(chan wsConn, 100)
 
 	// Instantiate all brokers
 	for i := uint(0); i < brokersNum; i++ {
-		br := NewBroker(i, eventBus, clientsPerBroker, bp.ConnectionsChan)
+		br := newBroker(i, eventBus, clientsPerBroker, bp.ConnectionsChan)
 		bp.workers = append(bp.workers, br)
 	}
 
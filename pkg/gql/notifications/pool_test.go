@@ -18,18 +18,25 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/syncprogress"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
 )
 
 type mockWebsocketConn struct {
 	mu     sync.RWMutex
 	msgBuf map[string]bool
+	msgs   []string
 }
 
 func (c *mockWebsocketConn) WriteMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	c.msgs = append(c.msgs, string(data))
+	c.mu.Unlock()
+
 	// Mimic connection consuming the json msg
 	var p BlockMsg
 	if err := json.Unmarshal(data, &p); err == nil {
@@ -131,3 +138,79 @@ func TestPoolBasicScenario(t *testing.T) {
 		t.Fatal("invalid test context")
 	}
 }
+
+func TestSyncProgressPool(t *testing.T) {
+	eb := eventbus.New()
+
+	pool := NewSyncProgressPool(eb, 1, 1)
+	defer pool.Close()
+
+	conn := &mockWebsocketConn{msgBuf: make(map[string]bool)}
+	pool.ConnectionsChan <- conn
+
+	time.Sleep(100 * time.Millisecond)
+
+	p := syncprogress.Progress{
+		CurrentHeight: 10,
+		TargetHeight:  20,
+		Peer:          "peer_a",
+		State:         syncprogress.StateOutSync,
+	}
+
+	errList := eb.Publish(topics.SyncProgress, message.New(topics.SyncProgress, p))
+	require.Empty(t, errList)
+
+	time.Sleep(500 * time.Millisecond)
+
+	conn.mu.RLock()
+	defer conn.mu.RUnlock()
+
+	require.Len(t, conn.msgs, 1)
+
+	var received syncprogress.Progress
+	require.NoError(t, json.Unmarshal([]byte(conn.msgs[0]), &received))
+	require.Equal(t, p, received)
+}
+
+func TestMempoolPool(t *testing.T) {
+	eb := eventbus.New()
+
+	pool := NewMempoolPool(eb, 1, 1)
+	defer pool.Close()
+
+	conn := &mockWebsocketConn{msgBuf: make(map[string]bool)}
+	pool.ConnectionsChan <- conn
+
+	time.Sleep(100 * time.Millisecond)
+
+	events := []mempool.Event{
+		{Type: mempool.Accepted, TxID: []byte{1, 2}},
+		{Type: mempool.Rejected, TxID: []byte{3, 4}, Reason: "already exists"},
+		{Type: mempool.Included, TxID: []byte{1, 2}, Height: 7},
+	}
+
+	for _, e := range events {
+		errList := eb.Publish(topics.MempoolEvent, message.New(topics.MempoolEvent, e))
+		require.Empty(t, errList)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	conn.mu.RLock()
+	defer conn.mu.RUnlock()
+
+	// Events are notified in order
+	require.Len(t, conn.msgs, len(events))
+
+	expected := []MempoolMsg{
+		{Type: "accepted", TxID: "0102"},
+		{Type: "rejected", TxID: "0304", Reason: "already exists"},
+		{Type: "included", TxID: "0102", Height: 7},
+	}
+
+	for i, msg := range conn.msgs {
+		var received MempoolMsg
+		require.NoError(t, json.Unmarshal([]byte(msg), &received))
+		require.Equal(t, expected[i], received)
+	}
+}
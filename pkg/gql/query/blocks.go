@@ -371,17 +371,11 @@ func (b blocks) fetchBlocksByHeights(db database.DB, from, to int64) ([]queryBlo
 			to = int64(tip)
 		}
 
-		for height := from; height <= to; height++ {
-			hash, err := t.FetchBlockHashByHeight(uint64(height))
-			if err != nil {
-				return err
-			}
-
-			header, err := t.FetchBlockHeader(hash)
-			if err != nil {
-				continue
-			}
+		if from > to || to < 0 {
+			return nil
+		}
 
+		return t.IterateHeaders(uint64(from), uint64(to), func(header *block.Header) (bool, error) {
 			// Reconstructing block with header only
 			b := &block.Block{
 				Header: header,
@@ -389,8 +383,8 @@ func (b blocks) fetchBlocksByHeights(db database.DB, from, to int64) ([]queryBlo
 			}
 
 			blocks = append(blocks, b)
-		}
-		return nil
+			return true, nil
+		})
 	})
 
 	qbs := make([]queryBlock, len(blocks))
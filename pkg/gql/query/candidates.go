@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package query
+
+import (
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/graphql-go/graphql"
+)
+
+const candidateRoundArg = "round"
+
+// queryCandidate is a data-wrapper for the candidate block fields that can be
+// fetched via graphql. Unlike accepted blocks, candidate transactions are
+// not stored in the chain, hence only their count is reported.
+type queryCandidate struct {
+	Hash               []byte
+	Height             uint64
+	Timestamp          int64
+	PrevBlockHash      []byte
+	StateHash          []byte
+	GeneratorBlsPubkey []byte
+	TxsCount           int
+}
+
+// File purpose is to define all arguments and resolvers relevant to
+// "candidates" query only.
+
+type candidates struct{}
+
+func (c candidates) getQuery() *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewList(Candidate),
+		Args: graphql.FieldConfigArgument{
+			candidateRoundArg: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: c.resolve,
+	}
+}
+
+func (c candidates) resolve(p graphql.ResolveParams) (interface{}, error) {
+	// Retrieve DB conn from context
+	db, ok := p.Context.Value("database").(database.DB)
+	if !ok {
+		return nil, errors.New("context does not store database conn")
+	}
+
+	round, ok := p.Args[candidateRoundArg].(int)
+	if !ok || round < 0 {
+		return nil, errors.New("invalid round")
+	}
+
+	qcs := make([]queryCandidate, 0)
+
+	err := db.View(func(t database.Transaction) error {
+		cms, err := t.FetchCandidateMessagesByRound(uint64(round))
+		if err != nil {
+			return err
+		}
+
+		for _, cm := range cms {
+			qcs = append(qcs, queryCandidate{
+				Hash:               cm.Header.Hash,
+				Height:             cm.Header.Height,
+				Timestamp:          cm.Header.Timestamp,
+				PrevBlockHash:      cm.Header.PrevBlockHash,
+				StateHash:          cm.Header.StateHash,
+				GeneratorBlsPubkey: cm.Header.GeneratorBlsPubkey,
+				TxsCount:           len(cm.Txs),
+			})
+		}
+
+		return nil
+	})
+
+	return qcs, err
+}
@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package query
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCandidatesByRound(t *testing.T) {
+	cm := helper.RandomBlock(5, 2)
+
+	assert.NoError(t, db.Update(func(t database.Transaction) error {
+		return t.StoreCandidateMessage(*cm)
+	}))
+
+	query := `
+		{
+		  candidates(round: 5) {
+			hash
+			height
+			txscount
+		  },
+		  none: candidates(round: 6) {
+			hash
+		  }
+		}
+		`
+	response := fmt.Sprintf(`
+		{
+		  "data": {
+			"candidates": [
+			  {
+				"hash": "%s",
+				"height": 5,
+				"txscount": %d
+			  }
+			],
+			"none": []
+		  }
+		}
+	`, hex.EncodeToString(cm.Header.Hash), len(cm.Txs))
+	assertQuery(t, query, response)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 
 	txs "github.com/dusk-network/dusk-blockchain/pkg/core/data/ipc/transactions"
+	pool "github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
 	"github.com/graphql-go/graphql"
@@ -72,3 +73,28 @@ func (t mempool) resolve(p graphql.ResolveParams) (interface{}, error) {
 
 	return nil, nil
 }
+
+func (t mempool) getViewQuery() *graphql.Field {
+	return &graphql.Field{
+		Type:    MempoolView,
+		Resolve: t.resolveView,
+	}
+}
+
+func (t mempool) resolveView(p graphql.ResolveParams) (interface{}, error) {
+	timeoutGetMempoolView := time.Duration(config.Get().Timeout.TimeoutGetMempoolTXs) * time.Second
+
+	resp, err := t.rpcBus.Call(topics.GetMempoolView, rpcbus.NewRequest(bytes.Buffer{}), timeoutGetMempoolView)
+	if err != nil {
+		return nil, err
+	}
+
+	v := resp.(pool.View)
+
+	return map[string]interface{}{
+		"txscount":   v.TxsCount,
+		"size":       v.Size,
+		"maxsize":    v.MaxSize,
+		"minfeerate": v.MinFeeRate,
+	}, nil
+}
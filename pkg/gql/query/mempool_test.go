@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	pool "github.com/dusk-network/dusk-blockchain/pkg/core/mempool"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/rpcbus"
+	"github.com/graphql-go/graphql"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestMempoolView(t *testing.T) {
+	assert := assert.New(t)
+
+	rpcBus := rpcbus.New()
+
+	reqChan := make(chan rpcbus.Request, 1)
+	assert.NoError(rpcBus.Register(topics.GetMempoolView, reqChan))
+
+	go func() {
+		r := <-reqChan
+		r.RespChan <- rpcbus.NewResponse(pool.View{
+			TxsCount:   2,
+			Size:       1500,
+			MaxSize:    100000000,
+			MinFeeRate: 0.5,
+		}, nil)
+	}()
+
+	schema, err := graphql.NewSchema(
+		graphql.SchemaConfig{Query: NewRoot(rpcBus).Query},
+	)
+	assert.NoError(err)
+
+	query := `
+		{
+			mempoolview {
+				txscount
+				size
+				maxsize
+				minfeerate
+			}
+		}
+	`
+
+	response := `
+		{
+			"data": {
+				"mempoolview": {
+					"txscount": 2,
+					"size": 1500,
+					"maxsize": 100000000,
+					"minfeerate": 0.5
+				}
+			}
+		}
+	`
+
+	result, err := json.Marshal(execute(query, schema, db))
+	assert.NoError(err)
+
+	equal, err := assertJSONs(result, []byte(response))
+	assert.NoError(err)
+	assert.True(equal)
+}
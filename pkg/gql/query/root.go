@@ -16,7 +16,8 @@ type Root struct {
 	Query *graphql.Object
 }
 
-// NewRoot returns a Root with blocks, transactions and mempool setup.
+// NewRoot returns a Root with blocks, candidates, transactions, mempool and
+// mempool view setup.
 func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 	m := mempool{rpcBus: rpcBus}
 
@@ -26,8 +27,10 @@ func NewRoot(rpcBus *rpcbus.RPCBus) *Root {
 				Name: "Query",
 				Fields: graphql.Fields{
 					"blocks":       blocks{}.getQuery(),
+					"candidates":   candidates{}.getQuery(),
 					"transactions": transactions{}.getQuery(),
 					"mempool":      m.getQuery(),
+					"mempoolview":  m.getViewQuery(),
 				},
 			},
 		),
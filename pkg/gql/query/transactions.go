@@ -29,6 +29,8 @@ const (
 	txlastArg     = "last"
 	txblocksArg   = "blocks"
 	txblocksRange = "blocksrange"
+	nullifierArg  = "nullifier"
+	contractArg   = "contract"
 )
 
 type (
@@ -131,6 +133,12 @@ func (t transactions) getQuery() *graphql.Field {
 			txblocksRange: &graphql.ArgumentConfig{
 				Type: graphql.NewList(graphql.Int),
 			},
+			nullifierArg: &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+			contractArg: &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
 		},
 		Resolve: t.resolve,
 	}
@@ -155,6 +163,21 @@ func (t transactions) resolve(p graphql.ResolveParams) (interface{}, error) {
 		return t.fetchTxsByHash(db, ids)
 	}
 
+	nullifier, ok := p.Args[nullifierArg].(string)
+	if ok {
+		return t.fetchTxByNullifier(db, nullifier)
+	}
+
+	contract, ok := p.Args[contractArg].(string)
+	if ok {
+		count, ok := p.Args[txlastArg].(int)
+		if !ok {
+			count = txsFetchLimit
+		}
+
+		return t.fetchTxsByContract(db, contract, count)
+	}
+
 	heightRange, found := p.Args[txblocksRange].([]interface{})
 	if found && len(heightRange) == 2 {
 		from, isint := heightRange[0].(int)
@@ -221,6 +244,70 @@ func (t transactions) fetchTxsByHash(db database.DB, txids []interface{}) ([]que
 	return txs, err
 }
 
+// Fetch the confirmed tx that spent a nullifier.
+func (t transactions) fetchTxByNullifier(db database.DB, nullifier string) ([]queryTx, error) {
+	txs := make([]queryTx, 0)
+
+	n, err := hex.DecodeString(nullifier)
+	if err != nil {
+		return txs, err
+	}
+
+	err = db.View(func(t database.Transaction) error {
+		tx, _, hash, err := t.FetchTxByNullifier(n)
+		if err != nil {
+			return err
+		}
+
+		header, err := t.FetchBlockHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		d, err := newQueryTx(tx, header.Hash, header.Timestamp, header.Height)
+		if err == nil {
+			txs = append(txs, d)
+		}
+
+		return nil
+	})
+
+	return txs, err
+}
+
+// Fetch up to `count` number of the latest confirmed txs calling a contract.
+func (t transactions) fetchTxsByContract(db database.DB, contract string, count int) ([]queryTx, error) {
+	if count <= 0 || count > txsFetchLimit {
+		msg := "invalid ``" + txlastArg + "`` argument"
+		log.WithField("txsFetchLimit", txsFetchLimit).
+			Warn(msg)
+		return make([]queryTx, 0), errors.New(msg)
+	}
+
+	contractID, err := hex.DecodeString(contract)
+	if err != nil {
+		return make([]queryTx, 0), err
+	}
+
+	var txIDs [][]byte
+
+	err = db.View(func(t database.Transaction) error {
+		var err error
+		txIDs, err = t.FetchTxsByContract(contractID, count)
+		return err
+	})
+	if err != nil {
+		return make([]queryTx, 0), err
+	}
+
+	ids := make([]interface{}, len(txIDs))
+	for i, txID := range txIDs {
+		ids[i] = hex.EncodeToString(txID)
+	}
+
+	return t.fetchTxsByHash(db, ids)
+}
+
 // Fetch `count` number of txs from lastly `maxBlocks` accepted blocks.
 func (t transactions) fetchLastTxs(db database.DB, count int, maxBlocks int) ([]queryTx, error) {
 	txs := make([]queryTx, 0)
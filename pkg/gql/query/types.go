@@ -79,6 +79,36 @@ var Header = graphql.NewObject(
 	},
 )
 
+// Candidate is the graphql object representing candidate blocks.
+var Candidate = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Candidate",
+		Fields: graphql.Fields{
+			"hash": &graphql.Field{
+				Type: Hex,
+			},
+			"height": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"timestamp": &graphql.Field{
+				Type: UnixTimestamp,
+			},
+			"prevblockhash": &graphql.Field{
+				Type: Hex,
+			},
+			"statehash": &graphql.Field{
+				Type: Hex,
+			},
+			"generatorblspubkey": &graphql.Field{
+				Type: Hex,
+			},
+			"txscount": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
 // Transaction is the graphql object representing transactions.
 var Transaction = graphql.NewObject(
 	graphql.ObjectConfig{
@@ -130,6 +160,27 @@ var Transaction = graphql.NewObject(
 	},
 )
 
+// MempoolView is the graphql object representing the mempool state.
+var MempoolView = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "MempoolView",
+		Fields: graphql.Fields{
+			"txscount": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"size": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"maxsize": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"minfeerate": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
 // ContractInfo is the graphql object representing Intercontract Call.
 var ContractInfo = graphql.NewObject(
 	graphql.ObjectConfig{
@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package kadcast
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+)
+
+// banList keeps the hosts of the peers banned by the scoring service.
+// Connections are managed by the Rusk network service, hence messages from
+// banned peers are discarded until the end of their ban.
+type banList struct {
+	lock   sync.RWMutex
+	banned map[string]time.Time
+}
+
+// newBanList creates a banList listening to topics.BanPeer.
+func newBanList(subscriber eventbus.Subscriber) *banList {
+	b := &banList{banned: make(map[string]time.Time)}
+
+	subscriber.Subscribe(topics.BanPeer, eventbus.NewSafeCallbackListener(func(m message.Message) {
+		b.add(m.Payload().(scoring.Ban))
+	}))
+
+	return b
+}
+
+func (b *banList) add(ban scoring.Ban) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.banned[ban.Host] = ban.Until
+}
+
+// isBanned returns true if the peer with the given address is banned. Expired
+// bans are dropped.
+func (b *banList) isBanned(addr string, now time.Time) bool {
+	host := scoring.Host(addr)
+
+	b.lock.RLock()
+	until, ok := b.banned[host]
+	b.lock.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	if now.Before(until) {
+		return true
+	}
+
+	b.lock.Lock()
+	delete(b.banned, host)
+	b.lock.Unlock()
+
+	return false
+}
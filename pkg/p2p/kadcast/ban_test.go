@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package kadcast
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/dusk-network/dusk-protobuf/autogen/go/rusk"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBannedPeer ensures messages from a banned peer are discarded until the
+// end of its ban.
+func TestBannedPeer(t *testing.T) {
+	assert := assert.New(t)
+
+	eb := eventbus.New()
+	p := peer.NewMessageProcessor(eb)
+	g := protocol.NewGossip()
+
+	received := make(chan string, 2)
+
+	p.Register(topics.Block, func(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
+		received <- srcPeerID
+		return nil, nil
+	})
+
+	b := newBanList(eb)
+	r := &Reader{publisher: eb, processor: p, gossip: g, banned: b}
+
+	until := time.Now().Add(time.Hour)
+	ban := scoring.Ban{Host: "10.0.0.1", Until: until}
+	eb.Publish(topics.BanPeer, message.New(topics.BanPeer, ban))
+
+	assert.Eventually(func() bool {
+		return b.isBanned("10.0.0.1:7000", time.Now())
+	}, time.Second, 10*time.Millisecond)
+
+	send := func(addr string) {
+		buf, err := createBlockMessage()
+		assert.NoError(err)
+		assert.NoError(g.Process(buf))
+
+		r.processMessage(&rusk.Message{
+			Message:  buf.Bytes(),
+			Metadata: &rusk.MessageMetadata{KadcastHeight: 1, SrcAddress: addr},
+		})
+	}
+
+	send("10.0.0.1:7000")
+	send("10.0.0.2:7000")
+
+	assert.Equal("10.0.0.2:7000", <-received)
+	assert.Empty(received)
+
+	// The ban is lifted once expired
+	assert.False(b.isBanned("10.0.0.1:7000", until.Add(time.Second)))
+	assert.False(b.isBanned("10.0.0.1:7000", time.Now()))
+}
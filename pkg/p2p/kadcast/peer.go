@@ -35,6 +35,9 @@ type Peer struct {
 	writers []ring.Writer
 	reader  *Reader
 
+	// peers banned by the scoring service
+	banned *banList
+
 	connections []*grpc.ClientConn
 
 	ctx    context.Context
@@ -51,6 +54,7 @@ func NewKadcastPeer(pCtx context.Context, eventBus *eventbus.EventBus, processor
 		cancel:      cancel,
 		ctx:         ctx,
 		connections: make([]*grpc.ClientConn, 0),
+		banned:      newBanList(eventBus),
 	}
 }
 
@@ -74,6 +78,7 @@ func (p *Peer) Launch() {
 	// a reader for Kadcast messages
 	client, conn := CreateNetworkClient(ctx, cfg.Grpc.Network, cfg.Grpc.Address, cfg.Grpc.DialTimeout)
 	p.reader = NewReader(ctx, p.eventBus, p.gossip, p.processor, client)
+	p.reader.banned = p.banned
 
 	p.connections = append(p.connections, conn)
 
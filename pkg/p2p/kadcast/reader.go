@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"time"
 
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/checksum"
@@ -34,6 +35,9 @@ type Reader struct {
 
 	client rusk.NetworkClient
 
+	// banned filters out messages from banned peers, if set.
+	banned *banList
+
 	ctx context.Context
 }
 
@@ -75,6 +79,12 @@ func (r *Reader) Listen() {
 
 // processMessage propagates the received kadcast message into the event bus.
 func (r *Reader) processMessage(msg *rusk.Message) {
+	if r.banned != nil && r.banned.isBanned(msg.Metadata.SrcAddress, time.Now()) {
+		log.WithField("r_addr", msg.Metadata.SrcAddress).
+			Debugln("discard message from banned peer")
+		return
+	}
+
 	reader := bytes.NewReader(msg.Message)
 
 	// read message (extract length and magic)
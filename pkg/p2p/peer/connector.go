@@ -16,6 +16,7 @@ import (
 
 	"github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/consensus/capi"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/scoring"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/protocol"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
@@ -30,7 +31,11 @@ const (
 	peerCountTime         = 30 * time.Second
 )
 
-var plog = logrus.WithField("process", "peer_conn")
+var (
+	plog = logrus.WithField("process", "peer_conn")
+
+	errBannedPeer = errors.New("peer is banned")
+)
 
 type connectFunc func(context.Context, *Reader, *Writer)
 
@@ -43,8 +48,12 @@ type Connector struct {
 
 	l net.Listener
 
-	lock     sync.RWMutex
-	registry map[string]struct{}
+	lock sync.RWMutex
+	// registry maps the address of connected peers to the function
+	// terminating the connection.
+	registry map[string]func()
+	// banned maps the host of banned peers to the end of their ban.
+	banned map[string]time.Time
 
 	services protocol.ServiceFlag
 
@@ -70,13 +79,18 @@ func NewConnector(eb eventbus.Broker, gossip *protocol.Gossip, port string,
 		gossip:        gossip,
 		readerFactory: NewReaderFactory(processor),
 		l:             listener,
-		registry:      make(map[string]struct{}),
+		registry:      make(map[string]func()),
+		banned:        make(map[string]time.Time),
 		services:      services,
 		connectFunc:   connectFunc,
 	}
 
 	processor.Register(topics.Addr, c.ProcessNewAddress)
 
+	eb.Subscribe(topics.BanPeer, eventbus.NewSafeCallbackListener(func(m message.Message) {
+		c.ban(m.Payload().(scoring.Ban))
+	}))
+
 	go func(c *Connector) {
 		for {
 			conn, err := c.l.Accept()
@@ -144,6 +158,10 @@ func (c *Connector) ProcessNewAddress(srcPeerID string, m message.Message) ([]by
 // Connect dials a connection with its string, then on succession
 // we pass the connection and the address to the OnConn method.
 func (c *Connector) Connect(addr string) error {
+	if c.isBanned(addr) {
+		return errBannedPeer
+	}
+
 	conn, err := c.Dial(addr)
 	if err != nil {
 		return err
@@ -168,9 +186,18 @@ func (c *Connector) Dial(addr string) (net.Conn, error) {
 }
 
 func (c *Connector) acceptConnection(conn net.Conn) {
+	raddr := conn.RemoteAddr().String()
+
+	if c.isBanned(raddr) {
+		plog.WithField("r_addr", raddr).WithField("type", "inbound").
+			Debugln("refuse banned peer")
+
+		_ = conn.Close()
+		return
+	}
+
 	pConn := NewConnection(conn, c.gossip)
 	peerReader := c.readerFactory.SpawnReader(pConn)
-	raddr := conn.RemoteAddr().String()
 
 	if err := peerReader.Accept(c.services); err != nil {
 		plog.WithField("r_addr", raddr).
@@ -183,14 +210,10 @@ func (c *Connector) acceptConnection(conn net.Conn) {
 	plog.WithField("r_addr", raddr).WithField("type", "inbound").
 		Infoln("peer_connection established")
 
-	c.addPeer(peerReader.Addr())
-
 	peerWriter := NewWriter(pConn, c.eventBus)
 
-	go func() {
-		c.connectFunc(context.Background(), peerReader, peerWriter)
-		c.removePeer(peerReader.Addr())
-	}()
+	c.connect(peerReader, peerWriter, pConn)
+
 }
 
 func (c *Connector) proposeConnection(conn net.Conn) {
@@ -211,19 +234,67 @@ func (c *Connector) proposeConnection(conn net.Conn) {
 
 	peerReader := c.readerFactory.SpawnReader(pConn)
 
-	c.addPeer(peerWriter.Addr())
+	c.connect(peerReader, peerWriter, pConn)
+}
+
+// connect runs the connectFunc of an established connection until it
+// terminates, or until the peer is banned.
+func (c *Connector) connect(peerReader *Reader, peerWriter *Writer, pConn *Connection) {
+	ctx, cancel := context.WithCancel(context.Background())
+	address := pConn.Addr()
+
+	c.addPeer(address, func() {
+		cancel()
+		_ = pConn.Close()
+	})
 
 	go func() {
-		c.connectFunc(context.Background(), peerReader, peerWriter)
-		c.removePeer(peerWriter.Addr())
+		c.connectFunc(ctx, peerReader, peerWriter)
+		cancel()
+		c.removePeer(address)
 	}()
 }
 
-func (c *Connector) addPeer(address string) {
+func (c *Connector) addPeer(address string, disconnect func()) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.registry[address] = struct{}{}
+	c.registry[address] = disconnect
+}
+
+// ban disconnects all peers of the banned host, and refuses them until the
+// end of the ban.
+func (c *Connector) ban(b scoring.Ban) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.banned[b.Host] = b.Until
+
+	for address, disconnect := range c.registry {
+		if scoring.Host(address) == b.Host {
+			plog.WithField("r_addr", address).Warnln("disconnect banned peer")
+			disconnect()
+		}
+	}
+}
+
+func (c *Connector) isBanned(address string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	host := scoring.Host(address)
+
+	until, ok := c.banned[host]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(until) {
+		delete(c.banned, host)
+		return false
+	}
+
+	return true
 }
 
 func (c *Connector) removePeer(address string) {
@@ -25,6 +25,9 @@ var routingRegistry = map[protocol.ServiceFlag]map[topics.Topic]struct{}{
 		topics.Pong:          {},
 		topics.GetData:       {},
 		topics.GetBlocks:     {},
+		topics.GetBlockRange: {},
+		topics.GetHeaders:    {},
+		topics.Headers:       {},
 		topics.Block:         {},
 		topics.MemPool:       {},
 		topics.Inv:           {},
@@ -11,11 +11,14 @@ import (
 	"errors"
 
 	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
 )
 
+var errInvalidRange = errors.New("invalid block range")
+
 // BlockHashBroker is a processing unit which handles GetBlocks messages.
 // It has a database connection, and a channel pointing to the outgoing message queue
 // of the requesting peer.
@@ -46,31 +49,79 @@ func (b *BlockHashBroker) AdvertiseMissingBlocks(srcPeerID string, m message.Mes
 	// and the chain tip.
 	inv := &message.Inv{}
 
-	for {
-		height++
+	err = b.db.View(func(t database.Transaction) error {
+		// Pruned blocks cannot be provided. Nothing is advertised if the
+		// peer misses any of them, so that it requests blocks from another
+		// peer rather than receiving an inventory it cannot link.
+		first, err := fetchFirstFullHeight(t)
+		if err != nil {
+			return err
+		}
 
-		var hash []byte
+		from := height + 1
+		if from < first {
+			return nil
+		}
 
-		err = b.db.View(func(t database.Transaction) error {
-			hash, err = t.FetchBlockHashByHeight(height)
-			return err
+		// Iteration stops once we pass the tip of the chain.
+		return t.IterateHeaders(from, from+uint64(cfg.MaxInvBlocks)-1, func(header *block.Header) (bool, error) {
+			inv.AddItem(message.InvTypeBlock, header.Hash)
+			return true, nil
 		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// This means we passed the tip of the chain, so we can exit the loop
-		// TODO: does it really, or do we need a more sophisticated way to go about it?
+	// If we retrieved any items, we should marshal the inventory message, and send it
+	// to the requesting peer.
+	if inv.InvList != nil {
+		buf, err := marshalInv(inv)
+		return []bytes.Buffer{buf}, err
+	}
+
+	return nil, nil
+}
+
+// AdvertiseBlockRange takes a GetBlockRange wire message, and returns an
+// inventory message of the blocks in the requested range. At most
+// config.MaxInvBlocks blocks are advertised.
+func (b *BlockHashBroker) AdvertiseBlockRange(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
+	msg := m.Payload().(message.GetBlockRange)
+	if msg.To < msg.From {
+		return nil, errInvalidRange
+	}
+
+	inv := &message.Inv{}
+
+	err := b.db.View(func(t database.Transaction) error {
+		// Pruned blocks cannot be provided. Nothing is advertised if the
+		// range includes any of them, see AdvertiseMissingBlocks.
+		first, err := fetchFirstFullHeight(t)
 		if err != nil {
-			break
+			return err
 		}
 
-		inv.AddItem(message.InvTypeBlock, hash)
+		from := msg.From
+		if from < first {
+			return nil
+		}
 
-		if len(inv.InvList) >= cfg.MaxInvBlocks {
-			break
+		to := msg.To
+		if to-from >= uint64(cfg.MaxInvBlocks) {
+			to = from + uint64(cfg.MaxInvBlocks) - 1
 		}
+
+		// Iteration stops once we pass the tip of the chain.
+		return t.IterateHeaders(from, to, func(header *block.Header) (bool, error) {
+			inv.AddItem(message.InvTypeBlock, header.Hash)
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// If we retrieved any items, we should marshal the inventory message, and send it
-	// to the requesting peer.
 	if inv.InvList != nil {
 		buf, err := marshalInv(inv)
 		return []bytes.Buffer{buf}, err
@@ -79,6 +130,64 @@ func (b *BlockHashBroker) AdvertiseMissingBlocks(srcPeerID string, m message.Mes
 	return nil, nil
 }
 
+// ProvideHeaders takes a GetHeaders wire message, and returns a headers
+// message with the block headers in the requested range. At most
+// config.MaxInvBlocks headers are provided.
+func (b *BlockHashBroker) ProvideHeaders(srcPeerID string, m message.Message) ([]bytes.Buffer, error) {
+	msg := m.Payload().(message.GetBlockRange)
+	if msg.To < msg.From {
+		return nil, errInvalidRange
+	}
+
+	to := msg.To
+	if to-msg.From >= uint64(cfg.MaxInvBlocks) {
+		to = msg.From + uint64(cfg.MaxInvBlocks) - 1
+	}
+
+	headers := &message.Headers{}
+
+	err := b.db.View(func(t database.Transaction) error {
+		// Iteration stops once we pass the tip of the chain.
+		return t.IterateHeaders(msg.From, to, func(header *block.Header) (bool, error) {
+			headers.Headers = append(headers.Headers, header)
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(headers.Headers) == 0 {
+		return nil, nil
+	}
+
+	buf := topics.Headers.ToBuffer()
+	if err := headers.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return []bytes.Buffer{buf}, nil
+}
+
+// fetchFirstFullHeight returns the height of the first block which is stored
+// in full, as transactions of older blocks may have been pruned.
+func fetchFirstFullHeight(t database.Transaction) (uint64, error) {
+	r, err := t.FetchRegistry()
+	if err == database.ErrStateNotFound {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !r.Pruned {
+		return 0, nil
+	}
+
+	return r.PrunedHeight + 1, nil
+}
+
 // Determine a peer's height from his locator hash.
 func (b *BlockHashBroker) fetchLocatorHeight(msg message.GetBlocks) (uint64, error) {
 	if len(msg.Locators) == 0 {
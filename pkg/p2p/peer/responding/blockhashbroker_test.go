@@ -7,10 +7,15 @@
 package responding_test
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
 	"testing"
 
+	cfg "github.com/dusk-network/dusk-blockchain/pkg/config"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/data/block"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database"
+	"github.com/dusk-network/dusk-blockchain/pkg/core/database/heavy"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/database/lite"
 	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
 	"github.com/dusk-network/dusk-blockchain/pkg/p2p/peer/responding"
@@ -54,6 +59,165 @@ func TestAdvertiseBlocks(t *testing.T) {
 	}
 }
 
+// Test the behavior of the block hash broker, upon receiving a GetBlockRange message.
+func TestAdvertiseBlockRange(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	hashes, blocks := generateBlocks(5)
+	assert.NoError(storeBlocks(db, blocks))
+
+	blockHashBroker := responding.NewBlockHashBroker(db)
+
+	msg := message.New(topics.GetBlockRange, message.GetBlockRange{From: 2, To: 3})
+	blksBuf, err := blockHashBroker.AdvertiseBlockRange("", msg)
+	assert.NoError(err)
+
+	topic, _ := topics.Extract(&blksBuf[0])
+	assert.Equal(topics.Inv, topic)
+
+	inv := &message.Inv{}
+	assert.NoError(inv.Decode(&blksBuf[0]))
+
+	// Only the blocks of the range are advertised
+	assert.Len(inv.InvList, 2)
+	assert.Equal(hashes[2], inv.InvList[0].Hash)
+	assert.Equal(hashes[3], inv.InvList[1].Hash)
+}
+
+// Test the behavior of the block hash broker, upon receiving a GetHeaders message.
+func TestProvideHeaders(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	hashes, blocks := generateBlocks(5)
+	assert.NoError(storeBlocks(db, blocks))
+
+	blockHashBroker := responding.NewBlockHashBroker(db)
+
+	// The range exceeds the chain tip
+	msg := message.New(topics.GetHeaders, message.GetBlockRange{From: 3, To: 10})
+	bufs, err := blockHashBroker.ProvideHeaders("", msg)
+	assert.NoError(err)
+
+	topic, _ := topics.Extract(&bufs[0])
+	assert.Equal(topics.Headers, topic)
+
+	headers := &message.Headers{}
+	assert.NoError(headers.Decode(&bufs[0]))
+
+	assert.Len(headers.Headers, 2)
+	assert.Equal(hashes[3], headers.Headers[0].Hash)
+	assert.Equal(hashes[4], headers.Headers[1].Hash)
+}
+
+// Test that a range ending before it starts is rejected.
+func TestInvalidBlockRange(t *testing.T) {
+	assert := assert.New(t)
+	_, db := lite.CreateDBConnection()
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	_, blocks := generateBlocks(5)
+	assert.NoError(storeBlocks(db, blocks))
+
+	blockHashBroker := responding.NewBlockHashBroker(db)
+	rng := message.GetBlockRange{From: 3, To: 2}
+
+	resp, err := blockHashBroker.AdvertiseBlockRange("", message.New(topics.GetBlockRange, rng))
+	assert.Error(err)
+	assert.Nil(resp)
+
+	resp, err = blockHashBroker.ProvideHeaders("", message.New(topics.GetHeaders, rng))
+	assert.Error(err)
+	assert.Nil(resp)
+}
+
+// Test that nothing is advertised to a peer missing pruned blocks, as they
+// cannot be provided.
+func TestAdvertisePrunedBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	orig := cfg.Get()
+	defer cfg.Mock(&orig)
+
+	r := cfg.Get()
+	r.Database.PruneDepth = 2
+	cfg.Mock(&r)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "pruned_store_")
+	assert.NoError(err)
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	drvr, err := database.From(heavy.DriverName)
+	assert.NoError(err)
+
+	db, err := drvr.Open(dir, false)
+	assert.NoError(err)
+
+	defer func() {
+		_ = db.Close()
+	}()
+
+	// Blocks up to height 2 are pruned once block 4 is persisted
+	hashes, blocks := generateBlocks(5)
+	assert.NoError(storeBlocks(db, blocks[:4]))
+	assert.NoError(db.Update(func(t database.Transaction) error {
+		return t.StoreBlock(blocks[4], true)
+	}))
+
+	blockHashBroker := responding.NewBlockHashBroker(db)
+
+	decodeInv := func(bufs []bytes.Buffer) *message.Inv {
+		topic, _ := topics.Extract(&bufs[0])
+		assert.Equal(topics.Inv, topic)
+
+		inv := &message.Inv{}
+		assert.NoError(inv.Decode(&bufs[0]))
+		return inv
+	}
+
+	// Nothing is advertised to a peer missing pruned blocks
+	bufs, err := blockHashBroker.AdvertiseMissingBlocks("", createGetBlocks(hashes[0]))
+	assert.NoError(err)
+	assert.Empty(bufs)
+
+	bufs, err = blockHashBroker.AdvertiseMissingBlocks("", createGetBlocks(hashes[2]))
+	assert.NoError(err)
+
+	inv := decodeInv(bufs)
+	assert.Len(inv.InvList, 2)
+	assert.Equal(hashes[3], inv.InvList[0].Hash)
+	assert.Equal(hashes[4], inv.InvList[1].Hash)
+
+	// Nothing is advertised for a range including pruned blocks
+	msg := message.New(topics.GetBlockRange, message.GetBlockRange{From: 1, To: 3})
+	bufs, err = blockHashBroker.AdvertiseBlockRange("", msg)
+	assert.NoError(err)
+	assert.Empty(bufs)
+
+	msg = message.New(topics.GetBlockRange, message.GetBlockRange{From: 3, To: 4})
+	bufs, err = blockHashBroker.AdvertiseBlockRange("", msg)
+	assert.NoError(err)
+
+	inv = decodeInv(bufs)
+	assert.Len(inv.InvList, 2)
+	assert.Equal(hashes[3], inv.InvList[0].Hash)
+}
+
 // Generate a set of random blocks, which follow each other up in the chain.
 func generateBlocks(amount int) ([][]byte, []*block.Block) {
 	var hashes [][]byte
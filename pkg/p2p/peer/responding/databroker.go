@@ -43,7 +43,7 @@ func (d *DataBroker) MarshalObjects(srcPeerID string, m message.Message) ([]byte
 	for _, obj := range msg.InvList {
 		switch obj.Type {
 		case message.InvTypeBlock:
-			// Fetch block from local state. It must be available
+			// Fetch block from local state. It must be available, unless pruned
 			var b *block.Block
 
 			err := d.db.View(func(t database.Transaction) error {
@@ -51,6 +51,12 @@ func (d *DataBroker) MarshalObjects(srcPeerID string, m message.Message) ([]byte
 				b, err = t.FetchBlock(obj.Hash)
 				return err
 			})
+
+			if err == database.ErrBlockPruned {
+				// Pruned blocks cannot be provided in full. Skip them.
+				continue
+			}
+
 			if err != nil {
 				return nil, err
 			}
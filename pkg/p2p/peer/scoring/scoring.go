@@ -0,0 +1,214 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+// Package scoring implements the peer scoring service. Components report
+// misbehaving peers by publishing a Misbehavior on topics.Misbehavior. Once the
+// score of a peer reaches the ban threshold, the Keeper publishes a Ban on
+// topics.BanPeer, upon which the peer is refused until the end of the
+// cooldown: the peer.Connector disconnects it, and the kadcast peer discards
+// its messages.
+package scoring
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/config"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message/payload"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("process", "scoring")
+
+// Scores of misbehaviors.
+const (
+	// InvalidBlock is scored for a block failing verification.
+	InvalidBlock uint32 = 50
+	// InvalidHeaders is scored for headers failing verification.
+	InvalidHeaders uint32 = 50
+	// InvalidConsensusMsg is scored for a malformed consensus message.
+	InvalidConsensusMsg uint32 = 20
+	// InvalidTx is scored for a transaction rejected by the mempool. It is
+	// kept low as honest peers may relay transactions which turned invalid.
+	InvalidTx uint32 = 5
+	// SyncTimeout is scored when a peer does not provide the requested
+	// blocks in time.
+	SyncTimeout uint32 = 10
+	// InvalidChallengeResponse is scored for a wrong response to the voucher
+	// challenge.
+	InvalidChallengeResponse uint32 = 50
+)
+
+const (
+	defaultBanThreshold = 100
+	defaultBanCooldown  = 3600
+)
+
+// Misbehavior of a peer. It is the payload of topics.Misbehavior events.
+type Misbehavior struct {
+	Address string
+	Score   uint32
+	Reason  string
+}
+
+// Copy a Misbehavior.
+// Implements the payload.Safe interface.
+func (m Misbehavior) Copy() payload.Safe {
+	return m
+}
+
+// Ban of a peer. It is the payload of topics.BanPeer events.
+type Ban struct {
+	// Host of the banned peer.
+	Host  string
+	Until time.Time
+}
+
+// Copy a Ban.
+// Implements the payload.Safe interface.
+func (b Ban) Copy() payload.Safe {
+	return b
+}
+
+// Report publishes a misbehavior of the peer with the given address. Messages
+// produced by the node itself have no source address, in which case nothing is
+// reported.
+func Report(publisher eventbus.Publisher, addr string, score uint32, reason string) {
+	if addr == "" {
+		return
+	}
+
+	m := Misbehavior{Address: addr, Score: score, Reason: reason}
+	publisher.Publish(topics.Misbehavior, message.New(topics.Misbehavior, m))
+}
+
+// Host returns the host of a peer address. Peers are scored and banned by host,
+// as the port of inbound connections is ephemeral.
+func Host(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
+
+type record struct {
+	score   uint32
+	updated time.Time
+}
+
+// Keeper keeps the misbehavior score of peers, and bans the ones reaching the
+// threshold. A score is reset once the peer did not misbehave for the cooldown
+// duration, and its record is dropped at the next expiry check.
+type Keeper struct {
+	publisher eventbus.Publisher
+	threshold uint32
+	cooldown  time.Duration
+
+	lock  sync.Mutex
+	peers map[string]*record
+}
+
+// NewKeeper creates a Keeper listening to topics.Misbehavior.
+func NewKeeper(eb eventbus.Broker) *Keeper {
+	threshold := config.Get().Network.BanThreshold
+	if threshold == 0 {
+		threshold = defaultBanThreshold
+	}
+
+	cooldown := config.Get().Network.BanCooldown
+	if cooldown == 0 {
+		cooldown = defaultBanCooldown
+	}
+
+	k := &Keeper{
+		publisher: eb,
+		threshold: threshold,
+		cooldown:  time.Duration(cooldown) * time.Second,
+		peers:     make(map[string]*record),
+	}
+
+	eb.Subscribe(topics.Misbehavior, eventbus.NewSafeCallbackListener(func(m message.Message) {
+		k.report(m.Payload().(Misbehavior), time.Now())
+	}))
+
+	go k.expireRecords()
+
+	return k
+}
+
+// expireRecords drops the expired records once every cooldown duration.
+func (k *Keeper) expireRecords() {
+	ticker := time.NewTicker(k.cooldown)
+
+	for {
+		now := <-ticker.C
+		k.expire(now)
+	}
+}
+
+// expire drops the records of peers which did not misbehave for the cooldown
+// duration.
+func (k *Keeper) expire(now time.Time) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	for host, r := range k.peers {
+		if now.Sub(r.updated) > k.cooldown {
+			delete(k.peers, host)
+		}
+	}
+}
+
+// Score returns the current score of a peer host.
+func (k *Keeper) Score(host string) uint32 {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if r, ok := k.peers[host]; ok {
+		return r.score
+	}
+
+	return 0
+}
+
+func (k *Keeper) report(m Misbehavior, now time.Time) {
+	host := Host(m.Address)
+
+	k.lock.Lock()
+
+	r, ok := k.peers[host]
+	if !ok || now.Sub(r.updated) > k.cooldown {
+		r = &record{}
+		k.peers[host] = r
+	}
+
+	r.score += m.Score
+	r.updated = now
+
+	l := log.WithField("r_addr", m.Address).
+		WithField("score", r.score).
+		WithField("reason", m.Reason)
+
+	if r.score < k.threshold {
+		k.lock.Unlock()
+		l.Debug("peer misbehaved")
+		return
+	}
+
+	delete(k.peers, host)
+	k.lock.Unlock()
+
+	l.Warn("ban peer")
+
+	ban := Ban{Host: host, Until: now.Add(k.cooldown)}
+	k.publisher.Publish(topics.BanPeer, message.New(topics.BanPeer, ban))
+}
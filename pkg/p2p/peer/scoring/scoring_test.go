@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/topics"
+	"github.com/dusk-network/dusk-blockchain/pkg/util/nativeutils/eventbus"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestBanOnThreshold(t *testing.T) {
+	assert := assert.New(t)
+
+	eb := eventbus.New()
+	banChan := make(chan message.Message, 1)
+	eb.Subscribe(topics.BanPeer, eventbus.NewChanListener(banChan))
+
+	k := NewKeeper(eb)
+	now := time.Now()
+
+	k.report(Misbehavior{Address: "10.0.0.1:7000", Score: InvalidBlock}, now)
+	assert.Equal(InvalidBlock, k.Score("10.0.0.1"))
+
+	// Peers are scored by host
+	k.report(Misbehavior{Address: "10.0.0.1:7001", Score: InvalidBlock}, now)
+
+	select {
+	case m := <-banChan:
+		ban := m.Payload().(Ban)
+		assert.Equal("10.0.0.1", ban.Host)
+		assert.Equal(now.Add(k.cooldown), ban.Until)
+	case <-time.After(time.Second):
+		t.Fatal("peer was not banned")
+	}
+
+	// The score is reset once banned
+	assert.Zero(k.Score("10.0.0.1"))
+}
+
+func TestScoreReset(t *testing.T) {
+	assert := assert.New(t)
+
+	eb := eventbus.New()
+	banChan := make(chan message.Message, 1)
+	eb.Subscribe(topics.BanPeer, eventbus.NewChanListener(banChan))
+
+	k := NewKeeper(eb)
+	now := time.Now()
+
+	k.report(Misbehavior{Address: "10.0.0.1:7000", Score: InvalidBlock}, now)
+
+	// No misbehavior for the cooldown duration
+	k.report(Misbehavior{Address: "10.0.0.1:7000", Score: InvalidBlock}, now.Add(k.cooldown+time.Second))
+	assert.Equal(InvalidBlock, k.Score("10.0.0.1"))
+
+	select {
+	case <-banChan:
+		t.Fatal("peer was banned")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestExpire(t *testing.T) {
+	assert := assert.New(t)
+
+	k := NewKeeper(eventbus.New())
+	now := time.Now()
+
+	k.report(Misbehavior{Address: "10.0.0.1:7000", Score: InvalidBlock}, now)
+	k.report(Misbehavior{Address: "10.0.0.2:7000", Score: InvalidBlock}, now.Add(k.cooldown))
+
+	// Only the record of the peer which did not misbehave for the cooldown
+	// duration is dropped
+	k.expire(now.Add(k.cooldown + time.Second))
+	assert.Len(k.peers, 1)
+	assert.Equal(InvalidBlock, k.Score("10.0.0.2"))
+}
+
+func TestReport(t *testing.T) {
+	assert := assert.New(t)
+
+	eb := eventbus.New()
+	msgChan := make(chan message.Message, 1)
+	eb.Subscribe(topics.Misbehavior, eventbus.NewChanListener(msgChan))
+
+	// Messages produced by the node itself are not reported
+	Report(eb, "", InvalidTx, "invalid transaction")
+	Report(eb, "10.0.0.1:7000", InvalidTx, "invalid transaction")
+
+	m := <-msgChan
+	assert.Equal(Misbehavior{Address: "10.0.0.1:7000", Score: InvalidTx, Reason: "invalid transaction"}, m.Payload())
+	assert.Empty(msgChan)
+}
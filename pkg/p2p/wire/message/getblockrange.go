@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package message
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/encoding"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message/payload"
+)
+
+// GetBlockRange defines a getblockrange message on the Dusk wire protocol. It
+// is used to request the blocks from height From to height To (included) from
+// another peer. Unlike GetBlocks, it does not require the hash of any block of
+// the range to be known by the requester.
+//
+// A getheaders message shares the same payload, and requests the headers of
+// the blocks in the range only.
+type GetBlockRange struct {
+	From uint64
+	To   uint64
+}
+
+// Copy a GetBlockRange message.
+// Implements the payload.Safe interface.
+func (g GetBlockRange) Copy() payload.Safe {
+	return g
+}
+
+// Encode a GetBlockRange struct and write it to w.
+func (g *GetBlockRange) Encode(w *bytes.Buffer) error {
+	if err := encoding.WriteUint64LE(w, g.From); err != nil {
+		return err
+	}
+
+	return encoding.WriteUint64LE(w, g.To)
+}
+
+// UnmarshalGetBlockRangeMessage unmarshals a GetBlockRange message into a
+// SerializableMessage.
+func UnmarshalGetBlockRangeMessage(r *bytes.Buffer, m SerializableMessage) error {
+	g := &GetBlockRange{}
+	if err := g.Decode(r); err != nil {
+		return err
+	}
+
+	m.SetPayload(*g)
+	return nil
+}
+
+// Decode a GetBlockRange struct from r into g.
+func (g *GetBlockRange) Decode(r *bytes.Buffer) error {
+	if err := encoding.ReadUint64LE(r, &g.From); err != nil {
+		return err
+	}
+
+	if err := encoding.ReadUint64LE(r, &g.To); err != nil {
+		return err
+	}
+
+	if g.From > g.To {
+		return errors.New("invalid range in GetBlockRange message")
+	}
+
+	return nil
+}
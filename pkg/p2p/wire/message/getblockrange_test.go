@@ -0,0 +1,36 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package message_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeGetBlockRange(t *testing.T) {
+	getBlockRange := &message.GetBlockRange{From: 11, To: 110}
+
+	buf := new(bytes.Buffer)
+	if err := getBlockRange.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	getBlockRange2 := &message.GetBlockRange{}
+	if err := getBlockRange2.Decode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, getBlockRange, getBlockRange2)
+
+	// A range ending before its start is rejected
+	buf.Reset()
+	assert.NoError(t, (&message.GetBlockRange{From: 110, To: 11}).Encode(buf))
+	assert.Error(t, getBlockRange2.Decode(buf))
+}
@@ -0,0 +1,40 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package message_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/core/tests/helper"
+	"github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeHeaders(t *testing.T) {
+	headers := &message.Headers{}
+
+	for i := 0; i < 5; i++ {
+		headers.Headers = append(headers.Headers, helper.RandomBlock(uint64(i), 1).Header)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := headers.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	headers2 := &message.Headers{}
+	if err := headers2.Decode(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, headers2.Headers, 5)
+
+	for i := range headers.Headers {
+		assert.True(t, headers.Headers[i].Equals(headers2.Headers[i]))
+	}
+}
@@ -244,6 +244,10 @@ func Unmarshal(b *bytes.Buffer, h *Metadata) (Message, error) {
 		err = UnmarshalBlockMessage(b, msg)
 	case topics.GetBlocks:
 		err = UnmarshalGetBlocksMessage(b, msg)
+	case topics.GetBlockRange, topics.GetHeaders:
+		err = UnmarshalGetBlockRangeMessage(b, msg)
+	case topics.Headers:
+		err = UnmarshalHeadersMessage(b, msg)
 	case topics.Inv, topics.GetData:
 		err = UnmarshalInvMessage(b, msg)
 	case topics.GetCandidate:
@@ -95,6 +95,20 @@ const (
 
 	// KadcastSendToMany send to many nodes.
 	KadcastSendToMany
+
+	// Data exchange topics added after the Kadcast topics to preserve the
+	// wire representation of the topics above.
+	GetBlockRange
+	GetHeaders
+	Headers
+
+	// Peer scoring topics.
+	Misbehavior
+	BanPeer
+
+	// Mempool topics.
+	MempoolEvent
+	DroppedTxs
 )
 
 type topicBuf struct {
@@ -156,6 +170,15 @@ var Topics = [...]topicBuf{
 	{GetCandidate, *(bytes.NewBuffer([]byte{byte(GetCandidate)})), "getcandidate"},
 	{SyncProgress, *(bytes.NewBuffer([]byte{byte(SyncProgress)})), "syncprogress"},
 	{Kadcast, *(bytes.NewBuffer([]byte{byte(Kadcast)})), "kadcast"},
+	{KadcastSendToOne, *(bytes.NewBuffer([]byte{byte(KadcastSendToOne)})), "kadcastsendtoone"},
+	{KadcastSendToMany, *(bytes.NewBuffer([]byte{byte(KadcastSendToMany)})), "kadcastsendtomany"},
+	{GetBlockRange, *(bytes.NewBuffer([]byte{byte(GetBlockRange)})), "getblockrange"},
+	{GetHeaders, *(bytes.NewBuffer([]byte{byte(GetHeaders)})), "getheaders"},
+	{Headers, *(bytes.NewBuffer([]byte{byte(Headers)})), "headers"},
+	{Misbehavior, *(bytes.NewBuffer([]byte{byte(Misbehavior)})), "misbehavior"},
+	{BanPeer, *(bytes.NewBuffer([]byte{byte(BanPeer)})), "banpeer"},
+	{MempoolEvent, *(bytes.NewBuffer([]byte{byte(MempoolEvent)})), "mempoolevent"},
+	{DroppedTxs, *(bytes.NewBuffer([]byte{byte(DroppedTxs)})), "droppedtxs"},
 }
 
 func checkConsistency(topics []topicBuf) {
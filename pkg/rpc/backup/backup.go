@@ -0,0 +1,55 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+// Package backup implements the node.Backup gRPC service streaming an online
+// backup of the chain database. The service is defined in backup.proto.
+//
+// A backup is a tar archive holding a Manifest under ManifestName and a copy
+// of the chain database under ChainDir.
+package backup
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. backup.proto
+
+import (
+	"bufio"
+	"io"
+)
+
+const (
+	// ManifestName is the name of the Manifest entry of a backup archive.
+	ManifestName = "backup.json"
+	// ChainDir is the directory of the chain database in a backup archive.
+	ChainDir = "chain"
+
+	// chunkSize is the max size of an archive chunk sent in a single message.
+	chunkSize = 64 * 1024
+)
+
+// Manifest describes the chain state a backup was taken at. StateRoot is the
+// Rusk state root at the tip, as of the backup. Rusk state must be restored to
+// PersistedStateRoot before starting a node from the backup, the blocks above
+// the persisted one being re-accepted on startup.
+type Manifest struct {
+	Driver             string `json:"driver"`
+	TipHash            string `json:"tip_hash"`
+	TipHeight          uint64 `json:"tip_height"`
+	PersistedHash      string `json:"persisted_hash"`
+	PersistedHeight    uint64 `json:"persisted_height"`
+	StateRoot          string `json:"state_root"`
+	PersistedStateRoot string `json:"persisted_state_root"`
+}
+
+// NewWriter returns a buffered writer sending the written data as chunks over
+// the stream. It must be flushed once done.
+func NewWriter(stream Backup_BackupServer) *bufio.Writer {
+	return bufio.NewWriterSize(&chunkWriter{stream}, chunkSize)
+}
+
+// NewReader returns a reader of the chunks received over the stream. It
+// returns io.EOF once the stream is successfully completed.
+func NewReader(stream Backup_BackupClient) io.Reader {
+	return &chunkReader{stream: stream}
+}
@@ -0,0 +1,100 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package backup_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// archiveServer streams a fixed archive, or fails once it is streamed.
+type archiveServer struct {
+	archive []byte
+	err     error
+}
+
+func (s *archiveServer) Backup(_ *emptypb.Empty, stream backup.Backup_BackupServer) error {
+	w := backup.NewWriter(stream)
+
+	if _, err := w.Write(s.archive); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return s.err
+}
+
+func dialBackup(t *testing.T, srv backup.BackupServer) backup.BackupClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	backup.RegisterBackupServer(s, srv)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return backup.NewBackupClient(conn)
+}
+
+// TestBackup ensures an archive spanning several chunks is received as a
+// whole.
+func TestBackup(t *testing.T) {
+	assert := require.New(t)
+
+	archive := make([]byte, 3*64*1024+123)
+	_, err := rand.Read(archive)
+	assert.NoError(err)
+
+	c := dialBackup(t, &archiveServer{archive: archive})
+
+	stream, err := c.Backup(context.Background(), &emptypb.Empty{})
+	assert.NoError(err)
+
+	received, err := ioutil.ReadAll(backup.NewReader(stream))
+	assert.NoError(err)
+	assert.Equal(archive, received)
+}
+
+// TestBackupFailure ensures a failed backup is not mistaken for a completed
+// one.
+func TestBackupFailure(t *testing.T) {
+	assert := require.New(t)
+
+	c := dialBackup(t, &archiveServer{
+		archive: []byte("partial"),
+		err:     errors.New("backup failed"),
+	})
+
+	stream, err := c.Backup(context.Background(), &emptypb.Empty{})
+	assert.NoError(err)
+
+	_, err = ioutil.ReadAll(backup.NewReader(stream))
+	assert.Error(err)
+}
@@ -0,0 +1,42 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+package backup
+
+// chunkWriter sends each write as a single message.
+type chunkWriter struct {
+	stream Backup_BackupServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&BackupChunk{Data: p}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// chunkReader reads the messages of a stream as a contiguous byte stream.
+type chunkReader struct {
+	stream Backup_BackupClient
+	chunk  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		m, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		r.chunk = m.Data
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
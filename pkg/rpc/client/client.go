@@ -195,6 +195,23 @@ func (i *AuthClientInterceptor) Unary() grpc.UnaryClientInterceptor {
 	}
 }
 
+// Stream returns the grpc stream interceptor. It attaches the session token
+// to the streams opened on non-open methods.
+func (i *AuthClientInterceptor) Stream() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !i.openMethods.Has([]byte(method)) {
+			tky, err := i.attachToken(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			return streamer(tky, desc, cc, method, opts...)
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
 // SetAccessToken sets the session token in a threadsafe way.
 func (i *AuthClientInterceptor) SetAccessToken(accessToken string) {
 	i.lock.Lock()
@@ -114,6 +114,34 @@ func (ai *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	}
 }
 
+// Stream returns a StreamServerInterceptor responsible for authentication.
+// The session is checked once, when the stream is opened.
+func (ai *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tag := "Stream call " + info.FullMethod
+		log.Tracef("%s", tag)
+
+		vctx, err := ai.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: vctx})
+	}
+}
+
+// authorizedStream is a grpc.ServerStream carrying the context of an
+// authorized call.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the authorized call.
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
 func (ai *AuthInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
 	if ai.openMethods.Has([]byte(method)) {
 		return ctx, nil
@@ -15,18 +15,45 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
 	"github.com/dusk-network/dusk-blockchain/pkg/rpc/client"
 	"github.com/dusk-network/dusk-blockchain/pkg/rpc/server"
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/syncprogress"
 	"github.com/dusk-network/dusk-protobuf/autogen/go/node"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var (
 	authClient   *client.AuthClient
 	walletClient node.WalletClient
+	backupClient backup.BackupClient
+	syncClient   syncprogress.SyncProgressClient
 )
 
+// backupData is streamed by backupServer.
+var backupData = []byte("backup")
+
+// backupServer streams backupData over a Backup stream.
+type backupServer struct{}
+
+func (backupServer) Backup(_ *emptypb.Empty, stream backup.Backup_BackupServer) error {
+	w := backup.NewWriter(stream)
+	if _, err := w.Write(backupData); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// syncServer streams a single sync progress.
+type syncServer struct{}
+
+func (syncServer) WatchSyncProgress(_ *emptypb.Empty, stream syncprogress.SyncProgress_WatchSyncProgressServer) error {
+	return stream.Send(syncprogress.Progress{State: "inSync"}.ToProto())
+}
+
 func init() {
 	log.SetLevel(log.ErrorLevel)
 }
@@ -51,6 +78,9 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
+	backup.RegisterBackupServer(grpcSrv, backupServer{})
+	syncprogress.RegisterSyncProgressServer(grpcSrv, syncServer{})
+
 	// get the server address from configuration
 	go serve(conf.Network, conf.Address, grpcSrv)
 
@@ -68,6 +98,7 @@ func TestMain(m *testing.M) {
 		grpc.WithAuthority("dummy"),
 		grpc.WithContextDialer(getDialer("unix")),
 		grpc.WithUnaryInterceptor(interceptor.Unary()),
+		grpc.WithStreamInterceptor(interceptor.Stream()),
 	)
 	if err != nil {
 		panic(err)
@@ -78,6 +109,9 @@ func TestMain(m *testing.M) {
 	// walletClient performs the wallet calls. It reuses the connection from
 	// the authClient
 	walletClient = node.NewWalletClient(conn)
+	// backupClient performs streaming calls
+	backupClient = backup.NewBackupClient(conn)
+	syncClient = syncprogress.NewSyncProgressClient(conn)
 
 	// run the tests
 	res := m.Run()
@@ -7,9 +7,15 @@
 package server_test
 
 import (
+	"context"
+	"io/ioutil"
 	"testing"
 
+	"github.com/dusk-network/dusk-blockchain/pkg/rpc/backup"
 	assert "github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 func TestCreateDropSession(t *testing.T) {
@@ -25,3 +31,55 @@ func TestCreateDropSession(t *testing.T) {
 	// recreate it
 	assert.Error(authClient.DropSession())
 }
+
+// TestStreamSession ensures streaming calls require a session too.
+func TestStreamSession(t *testing.T) {
+	assert := assert.New(t)
+
+	// The stream is rejected without a session
+	stream, err := backupClient.Backup(context.Background(), &emptypb.Empty{})
+	assert.NoError(err)
+
+	_, err = ioutil.ReadAll(backup.NewReader(stream))
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.CreateSession()
+	assert.NoError(err)
+
+	defer func() {
+		_ = authClient.DropSession()
+	}()
+
+	stream, err = backupClient.Backup(context.Background(), &emptypb.Empty{})
+	assert.NoError(err)
+
+	data, err := ioutil.ReadAll(backup.NewReader(stream))
+	assert.NoError(err)
+	assert.Equal(backupData, data)
+}
+
+// TestSyncProgressSession ensures the sync progress can be watched only within
+// a session.
+func TestSyncProgressSession(t *testing.T) {
+	assert := assert.New(t)
+
+	stream, err := syncClient.WatchSyncProgress(context.Background(), &emptypb.Empty{})
+	assert.NoError(err)
+
+	_, err = stream.Recv()
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.CreateSession()
+	assert.NoError(err)
+
+	defer func() {
+		_ = authClient.DropSession()
+	}()
+
+	stream, err = syncClient.WatchSyncProgress(context.Background(), &emptypb.Empty{})
+	assert.NoError(err)
+
+	p, err := stream.Recv()
+	assert.NoError(err)
+	assert.Equal("inSync", p.State)
+}
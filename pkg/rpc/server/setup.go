@@ -79,7 +79,7 @@ func SetupGRPC(conf Setup) (*grpc.Server, error) {
 		// instantiate the auth service and the interceptor
 		auth, authInterceptor := NewAuth(jwtMan)
 
-		// serverOpt = append(serverOpt, grpc.StreamInterceptor(streamInterceptor))
+		serverOpt = append(serverOpt, grpc.StreamInterceptor(authInterceptor.Stream()))
 		serverOpt = append(serverOpt, grpc.UnaryInterceptor(authInterceptor.Unary()))
 		grpcServer := grpc.NewServer(serverOpt...)
 
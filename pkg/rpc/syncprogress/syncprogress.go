@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT License was not distributed with this
+// file, you can obtain one at https://opensource.org/licenses/MIT.
+//
+// Copyright (c) DUSK NETWORK. All rights reserved.
+
+// Package syncprogress implements the node.SyncProgress gRPC service
+// streaming the progress of the chain synchronization. The service is defined
+// in syncprogress.proto.
+package syncprogress
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. syncprogress.proto
+
+import "github.com/dusk-network/dusk-blockchain/pkg/p2p/wire/message/payload"
+
+// States of the synchronizer.
+const (
+	StateInSync  = "insync"
+	StateOutSync = "outsync"
+)
+
+// Progress is a snapshot of the chain synchronization progress. It is the
+// payload of topics.SyncProgress events.
+type Progress struct {
+	CurrentHeight uint64 `json:"current_height"`
+	// TargetHeight is the highest block height seen from the network.
+	TargetHeight    uint64  `json:"target_height"`
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+	// ETA is the estimated time remaining to reach TargetHeight, in seconds.
+	ETA uint64 `json:"eta"`
+	// Peer is the address of the peer the node is syncing from, if any.
+	Peer  string `json:"peer"`
+	State string `json:"state"`
+}
+
+// Copy a Progress.
+// Implements the payload.Safe interface.
+func (p Progress) Copy() payload.Safe {
+	return p
+}
+
+// ToProto converts a Progress into a SyncProgressUpdate message.
+func (p Progress) ToProto() *SyncProgressUpdate {
+	return &SyncProgressUpdate{
+		CurrentHeight:   p.CurrentHeight,
+		TargetHeight:    p.TargetHeight,
+		BlocksPerSecond: p.BlocksPerSecond,
+		Eta:             p.ETA,
+		Peer:            p.Peer,
+		State:           p.State,
+	}
+}
+
+// FromProto converts a SyncProgressUpdate message into a Progress.
+func FromProto(m *SyncProgressUpdate) Progress {
+	return Progress{
+		CurrentHeight:   m.GetCurrentHeight(),
+		TargetHeight:    m.GetTargetHeight(),
+		BlocksPerSecond: m.GetBlocksPerSecond(),
+		ETA:             m.GetEta(),
+		Peer:            m.GetPeer(),
+		State:           m.GetState(),
+	}
+}